@@ -2,17 +2,22 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+	"github.com/9506hqwy/vmomi-event-source/pkg/credential"
 	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
 	"github.com/9506hqwy/vmomi-event-source/pkg/loki"
+	"github.com/9506hqwy/vmomi-event-source/pkg/output"
 	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
 )
 
@@ -51,6 +56,53 @@ var categoryCmd = &cobra.Command{
 	},
 }
 
+var catalogCmd = &cobra.Command{
+	Use:     "catalog",
+	Short:   "VMOMI Event Source Catalog",
+	Long:    "VMOMI Event Source Catalog",
+	Version: fmt.Sprintf("%s\nCommit: %s", version, commit),
+}
+
+var catalogExportCmd = &cobra.Command{
+	Use:     "export",
+	Short:   "VMOMI Event Source Catalog Export",
+	Long:    "VMOMI Event Source Catalog Export",
+	Version: fmt.Sprintf("%s\nCommit: %s", version, commit),
+	Run: func(cmd *cobra.Command, _ []string) {
+		ctx := context.Background()
+		ctx = fromArgument(ctx)
+
+		locales, err := cmd.Flags().GetStringSlice("locales")
+		if err != nil {
+			log.Fatalf("GetStringSlice error: %v", err)
+		}
+
+		filePath, err := cmd.Flags().GetString("file")
+		if err != nil {
+			log.Fatalf("GetString error: %v", err)
+		}
+
+		bundle, err := vmomi.ExportCatalogBundle(ctx, locales)
+		if err != nil {
+			log.Fatalf("ExportCatalogBundle error: %v", err)
+		}
+
+		data, err := json.MarshalIndent(bundle, "", "  ")
+		if err != nil {
+			log.Fatalf("MarshalIndent error: %v", err)
+		}
+
+		if filePath == "" {
+			_, err = os.Stdout.Write(data)
+		} else {
+			err = os.WriteFile(filePath, data, 0o600)
+		}
+		if err != nil {
+			log.Fatalf("Write error: %v", err)
+		}
+	},
+}
+
 var configCmd = &cobra.Command{
 	Use:     "config",
 	Short:   "VMOMI Event Source Config",
@@ -77,6 +129,72 @@ var configCmd = &cobra.Command{
 	},
 }
 
+var configGenerateCmd = &cobra.Command{
+	Use:     "generate",
+	Short:   "VMOMI Event Source Config Generate",
+	Long:    "VMOMI Event Source Config Generate",
+	Version: fmt.Sprintf("%s\nCommit: %s", version, commit),
+	Run: func(cmd *cobra.Command, _ []string) {
+		ctx := context.Background()
+		ctx = fromArgument(ctx)
+
+		excludeCategories, err := cmd.Flags().GetStringSlice("exclude-categories")
+		if err != nil {
+			log.Fatalf("GetStringSlice error: %v", err)
+		}
+
+		info, err := vmomi.GetEventInfo(ctx)
+		if err != nil {
+			log.Fatalf("GetEventInfo error: %v", err)
+		}
+
+		eventTypes := make([]config.EventType, len(info))
+		for i, e := range info {
+			eventTypes[i] = config.EventType{
+				EventTypeID: e.Key,
+				Category:    e.Category,
+				Description: e.Description,
+			}
+		}
+
+		_, err = fmt.Print(config.GenerateConfig(eventTypes, excludeCategories))
+		if err != nil {
+			log.Fatalf("Print error: %v", err)
+		}
+	},
+}
+
+var credentialCmd = &cobra.Command{
+	Use:     "credential",
+	Short:   "VMOMI Event Source Credential",
+	Long:    "VMOMI Event Source Credential",
+	Version: fmt.Sprintf("%s\nCommit: %s", version, commit),
+}
+
+var credentialEncryptCmd = &cobra.Command{
+	Use:     "encrypt",
+	Short:   "VMOMI Event Source Credential Encrypt",
+	Long:    "VMOMI Event Source Credential Encrypt",
+	Version: fmt.Sprintf("%s\nCommit: %s", version, commit),
+	Run: func(_ *cobra.Command, _ []string) {
+		var cred credential.Credential
+		err := json.NewDecoder(os.Stdin).Decode(&cred)
+		if err != nil {
+			log.Fatalf("Decode error: %v", err)
+		}
+
+		data, err := credential.Encrypt(&cred, viper.GetString("credential_key_file"))
+		if err != nil {
+			log.Fatalf("Encrypt error: %v", err)
+		}
+
+		_, err = os.Stdout.Write(data)
+		if err != nil {
+			log.Fatalf("Write error: %v", err)
+		}
+	},
+}
+
 var enumeratedCmd = &cobra.Command{
 	Use:     "enumerated",
 	Short:   "VMOMI Event Source Enumerated",
@@ -111,7 +229,9 @@ var infoCmd = &cobra.Command{
 	Short:   "VMOMI Event Source Info",
 	Long:    "VMOMI Event Source Info",
 	Version: fmt.Sprintf("%s\nCommit: %s", version, commit),
-	Run: func(_ *cobra.Command, _ []string) {
+	Run: func(cmd *cobra.Command, _ []string) {
+		w := newEventInfoWriter(cmd)
+
 		ctx := context.Background()
 		ctx = fromArgument(ctx)
 
@@ -121,32 +241,13 @@ var infoCmd = &cobra.Command{
 		}
 
 		for _, i := range info {
-			_, err := fmt.Println(
-				i.Key,
-				i.Category,
-				i.Description,
-			)
+			err := w.Write(output.EventInfoRecord(&i))
 			if err != nil {
 				log.Fatalf("Print error: %v", err)
 			}
-
-			if i.LongDescription.Description != "" {
-				_, err := fmt.Println(
-					"  Long Description:",
-					i.LongDescription.Description,
-				)
-				if err != nil {
-					log.Fatalf("Print error: %v", err)
-				}
-
-				for _, c := range i.LongDescription.Causes {
-					_, err := fmt.Println("  Cause:", c.Description)
-					if err != nil {
-						log.Fatalf("Print error: %v", err)
-					}
-				}
-			}
 		}
+
+		flushWriter(w)
 	},
 }
 
@@ -155,26 +256,62 @@ var eventCmd = &cobra.Command{
 	Short:   "VMOMI Event Source Event",
 	Long:    "VMOMI Event Source Event",
 	Version: fmt.Sprintf("%s\nCommit: %s", version, commit),
-	Run: func(_ *cobra.Command, _ []string) {
+	Run: func(cmd *cobra.Command, _ []string) {
+		w := newEventWriter(cmd)
+
+		begin, err := getTimeFlag(cmd, "begin")
+		if err != nil {
+			log.Fatalf("Get begin error: %v", err)
+		}
+
+		end, err := getTimeFlag(cmd, "end")
+		if err != nil {
+			log.Fatalf("Get end error: %v", err)
+		}
+
+		maxCount, err := cmd.Flags().GetInt("max")
+		if err != nil {
+			log.Fatalf("Get max error: %v", err)
+		}
+
 		ctx := context.Background()
 		ctx = fromArgument(ctx)
 
-		events, err := vmomi.Query(ctx)
+		cfg, err := config.GetConfig(ctx)
 		if err != nil {
-			log.Fatalf("Query error: %v", err)
+			log.Fatalf("GetConfig error: %v", err)
 		}
 
-		for _, event := range events {
-			_, err = fmt.Printf(
-				"%v\tuser=%v\tseverity=%v\ttarget=%v\tmessage=%v\n",
-				event.CreatedTime,
-				event.UserName,
-				event.Severity,
-				event.Target(),
-				event.FullFormattedMessage)
+		ctx = cfg.WithFilterContext(ctx)
+
+		if begin == nil && end == nil && maxCount == 0 {
+			events, err := vmomi.Query(ctx)
 			if err != nil {
-				log.Fatalf("Print error: %v", err)
+				log.Fatalf("Query error: %v", err)
 			}
+
+			printEvents(w, &events, cfg)
+			flushWriter(w)
+			return
+		}
+
+		ch := make(chan *[]vmomi.Event)
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- vmomi.QueryRange(ctx, begin, end, maxCount, ch)
+		}()
+
+		for events := range ch {
+			printEvents(w, events, cfg)
+		}
+
+		flushWriter(w)
+
+		// Exit after the channel is closed not to lose error.
+		err = <-errCh
+		if err != nil {
+			log.Fatalf("QueryRange error: %v", err)
 		}
 	},
 }
@@ -185,6 +322,8 @@ var waitCmd = &cobra.Command{
 	Long:    "VMOMI Event Source Wait",
 	Version: fmt.Sprintf("%s\nCommit: %s", version, commit),
 	Run: func(cmd *cobra.Command, _ []string) {
+		w := newEventWriter(cmd)
+
 		timeout, err := cmd.Flags().GetInt32("timeout")
 		if err != nil {
 			log.Fatalf("Get timeout error: %v", err)
@@ -193,28 +332,29 @@ var waitCmd = &cobra.Command{
 		ctx := context.Background()
 		ctx = fromArgument(ctx)
 
+		cfg, err := config.GetConfig(ctx)
+		if err != nil {
+			log.Fatalf("GetConfig error: %v", err)
+		}
+
+		ctx = cfg.WithFilterContext(ctx)
+
 		ch := make(chan *[]vmomi.Event)
+		errCh := make(chan error, 1)
 
 		go func() {
-			err := vmomi.Poll(ctx, &timeout, ch, 0)
-			if err != nil {
-				log.Fatalf("Poll error: %v", err)
-			}
+			errCh <- vmomi.Poll(ctx, &timeout, ch, nil)
 		}()
 
 		for events := range ch {
-			for _, event := range *events {
-				_, err := fmt.Printf(
-					"%v\tuser=%v\tseverity=%v\ttarget=%v\tmessage=%v\n",
-					event.CreatedTime,
-					event.UserName,
-					event.Severity,
-					event.Target(),
-					event.FullFormattedMessage)
-				if err != nil {
-					log.Fatalf("Print error: %v", err)
-				}
-			}
+			printEvents(w, events, cfg)
+		}
+
+		flushWriter(w)
+
+		err = <-errCh
+		if err != nil {
+			log.Fatalf("Poll error: %v", err)
 		}
 	},
 }
@@ -272,8 +412,90 @@ var lokiCollectCmd = &cobra.Command{
 	},
 }
 
+func newEventWriter(cmd *cobra.Command) output.Writer {
+	format, fields := getOutputFlags(cmd, output.EventFields())
+
+	if len(fields) == output.Empty && format == output.FormatText {
+		fields = output.EventTextFields()
+	}
+
+	w, err := output.NewWriter(os.Stdout, format, fields)
+	if err != nil {
+		log.Fatalf("NewWriter error: %v", err)
+	}
+
+	return w
+}
+
+func newEventInfoWriter(cmd *cobra.Command) output.Writer {
+	format, fields := getOutputFlags(cmd, output.EventInfoFields())
+
+	w, err := output.NewEventInfoWriter(os.Stdout, format, fields)
+	if err != nil {
+		log.Fatalf("NewWriter error: %v", err)
+	}
+
+	return w
+}
+
+func getOutputFlags(cmd *cobra.Command, names []string) (string, []string) {
+	format, err := cmd.Flags().GetString("output")
+	if err != nil {
+		log.Fatalf("Get output error: %v", err)
+	}
+
+	fields, err := cmd.Flags().GetStringSlice("fields")
+	if err != nil {
+		log.Fatalf("Get fields error: %v", err)
+	}
+
+	// Validate before calling API.
+	err = output.ValidateFields(fields, names)
+	if err != nil {
+		log.Fatalf("Get fields error: %v", err)
+	}
+
+	return format, fields
+}
+
+func printEvents(w output.Writer, events *[]vmomi.Event, cfg *config.Config) {
+	for _, event := range *events {
+		attributes := cfg.SelectAttributes(event.EventTypeID, event.GetAttributes)
+		event.Severity = cfg.GetSeverity(event.EventTypeID, event.Severity)
+		err := w.Write(output.EventRecord(&event, attributes))
+		if err != nil {
+			log.Fatalf("Print error: %v", err)
+		}
+	}
+}
+
+func flushWriter(w output.Writer) {
+	err := w.Flush()
+	if err != nil {
+		log.Fatalf("Print error: %v", err)
+	}
+}
+
 //revive:enable:deep-exit
 
+func getTimeFlag(cmd *cobra.Command, name string) (*time.Time, error) {
+	value, err := cmd.Flags().GetString(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
 //revive:disable:line-length-limit
 
 func fromArgument(ctx context.Context) context.Context {
@@ -282,11 +504,66 @@ func fromArgument(ctx context.Context) context.Context {
 	ctx = context.WithValue(ctx, flag.TargetPasswordKey{}, viper.GetString("target_password"))
 	ctx = context.WithValue(ctx, flag.TargetNoVerifySSLKey{}, viper.GetBool("target_no_verify_ssl"))
 	ctx = context.WithValue(ctx, flag.TargetTimeoutKey{}, viper.GetInt("target_timeout"))
+	ctx = context.WithValue(ctx, flag.TargetLocaleKey{}, viper.GetString("target_locale"))
+	ctx = context.WithValue(ctx, flag.TargetTokenFileKey{}, viper.GetString("target_token_file"))
+	ctx = context.WithValue(ctx, flag.TargetCertFileKey{}, viper.GetString("target_cert_file"))
+	ctx = context.WithValue(ctx, flag.TargetKeyFileKey{}, viper.GetString("target_key_file"))
+	ctx = context.WithValue(ctx, flag.TargetSessionDirKey{}, viper.GetString("target_session_dir"))
+	ctx = context.WithValue(ctx, flag.TargetCredentialKey{}, viper.GetString("target_credential"))
+	ctx = context.WithValue(ctx, flag.CredentialKeyFileKey{}, viper.GetString("credential_key_file"))
+	ctx = context.WithValue(ctx, flag.TargetCABundleKey{}, viper.GetStringSlice("target_ca_bundle"))
+	ctx = context.WithValue(ctx, flag.TargetThumbprintKey{}, viper.GetString("target_thumbprint"))
+	ctx = context.WithValue(
+		ctx,
+		flag.TargetTLSMinVersionKey{},
+		viper.GetString("target_tls_min_version"),
+	)
+	ctx = context.WithValue(
+		ctx,
+		flag.TargetTLSServerNameKey{},
+		viper.GetString("target_tls_server_name"),
+	)
+	ctx = context.WithValue(ctx, flag.TargetProxyKey{}, viper.GetString("target_proxy"))
+	ctx = context.WithValue(ctx, flag.TargetNoProxyKey{}, viper.GetStringSlice("target_no_proxy"))
+	ctx = context.WithValue(ctx, flag.TargetCatalogTTLKey{}, viper.GetInt("target_catalog_ttl"))
+	ctx = context.WithValue(ctx, flag.TargetCatalogCacheDirKey{}, viper.GetString("target_catalog_cache_dir"))
+	ctx = context.WithValue(ctx, flag.TargetCatalogBundleKey{}, viper.GetString("target_catalog_bundle"))
 	ctx = context.WithValue(ctx, flag.LogLevelKey{}, viper.GetString("log_level"))
 	ctx = context.WithValue(ctx, flag.LokiConfigKey{}, viper.GetString("config"))
 
+	ctx = context.WithValue(ctx, flag.LokiBatchMaxBytesKey{}, viper.GetInt("loki_batch_max_bytes"))
+	ctx = context.WithValue(ctx, flag.LokiBatchMaxEntriesKey{}, viper.GetInt("loki_batch_max_entries"))
+	ctx = context.WithValue(ctx, flag.LokiBatchMaxWaitKey{}, viper.GetInt("loki_batch_max_wait"))
+	ctx = context.WithValue(ctx, flag.LokiChainIdleTimeoutKey{}, viper.GetInt("loki_chain_idle_timeout"))
+	ctx = context.WithValue(ctx, flag.LokiCheckpointKey{}, viper.GetString("loki_checkpoint"))
+	ctx = context.WithValue(ctx, flag.LokiCollectAlarmsKey{}, viper.GetBool("loki_collect_alarms"))
+	ctx = context.WithValue(ctx, flag.LokiCollectTasksKey{}, viper.GetBool("loki_collect_tasks"))
+	ctx = context.WithValue(ctx, flag.LokiCorrelateChainsKey{}, viper.GetBool("loki_correlate_chains"))
+	ctx = context.WithValue(
+		ctx,
+		flag.LokiEnrichInventoryKey{},
+		viper.GetBool("loki_enrich_inventory"),
+	)
+	ctx = context.WithValue(
+		ctx,
+		flag.LokiMessageLocalesKey{},
+		viper.GetStringSlice("loki_message_locales"),
+	)
 	ctx = context.WithValue(ctx, flag.LokiURLKey{}, viper.GetString("loki_url"))
 	ctx = context.WithValue(ctx, flag.LokiTenantIDKey{}, viper.GetString("loki_tenant"))
+	ctx = context.WithValue(ctx, flag.LokiUserKey{}, viper.GetString("loki_user"))
+	ctx = context.WithValue(ctx, flag.LokiPasswordKey{}, viper.GetString("loki_password"))
+	ctx = context.WithValue(ctx, flag.LokiCredentialKey{}, viper.GetString("loki_credential"))
+	ctx = context.WithValue(ctx, flag.LokiCABundleKey{}, viper.GetStringSlice("loki_ca_bundle"))
+	ctx = context.WithValue(ctx, flag.LokiCertFileKey{}, viper.GetString("loki_cert_file"))
+	ctx = context.WithValue(ctx, flag.LokiKeyFileKey{}, viper.GetString("loki_key_file"))
+	ctx = context.WithValue(
+		ctx,
+		flag.LokiTLSMinVersionKey{},
+		viper.GetString("loki_tls_min_version"),
+	)
+	ctx = context.WithValue(ctx, flag.LokiProxyKey{}, viper.GetString("loki_proxy"))
+	ctx = context.WithValue(ctx, flag.LokiNoProxyKey{}, viper.GetStringSlice("loki_no_proxy"))
 	ctx = context.WithValue(ctx, flag.LokiNoVerifySSLKey{}, viper.GetBool("loki_no_verify_ssl"))
 	ctx = context.WithValue(ctx, flag.LokiServiceNameKey{}, viper.GetString("loki_service_name"))
 	return ctx
@@ -294,6 +571,8 @@ func fromArgument(ctx context.Context) context.Context {
 
 //revive:disable:add-constant
 
+//revive:disable:function-length
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
@@ -302,8 +581,34 @@ func init() {
 	rootCmd.PersistentFlags().String("password", "", "vSphere server password.")
 	rootCmd.PersistentFlags().Bool("no-verify-ssl", false, "Skip SSL verification.")
 	rootCmd.PersistentFlags().Int("timeout", 10, "API call timeout seconds.")
+	rootCmd.PersistentFlags().String("locale", "", "Message locale. (default session locale)")
 	rootCmd.PersistentFlags().String("log-level", "INFO", "Log level.")
 	rootCmd.PersistentFlags().String("config", "", "Config file path.")
+	rootCmd.PersistentFlags().String("token-file", "", "SAML token file path.")
+	rootCmd.PersistentFlags().String("cert-file", "", "Solution user certificate file path.")
+	rootCmd.PersistentFlags().String("key-file", "", "Solution user private key file path.")
+	rootCmd.PersistentFlags().String("session-dir", "", "Session directory path to reuse session.")
+	rootCmd.PersistentFlags().String("credential", "", "vSphere server credential source.")
+	rootCmd.PersistentFlags().String("credential-key-file", "", "Credential key file path.")
+	rootCmd.PersistentFlags().StringSlice("ca-bundle", []string{}, "CA bundle file paths.")
+	rootCmd.PersistentFlags().String("thumbprint", "", "vSphere server SHA-256 thumbprint.")
+	rootCmd.PersistentFlags().String("tls-min-version", "", "Minimum TLS version. (e.g. 1.2)")
+	rootCmd.PersistentFlags().String("tls-server-name", "", "TLS server name. (default host)")
+	rootCmd.PersistentFlags().String("proxy", "", "vSphere server proxy URL.")
+	rootCmd.PersistentFlags().StringSlice("no-proxy", []string{}, "Hosts not to use proxy.")
+	rootCmd.PersistentFlags().Int("catalog-ttl", 3600, "Catalog refresh interval seconds.")
+	rootCmd.PersistentFlags().String("catalog-cache-dir", "", "Catalog cache directory path.")
+	rootCmd.PersistentFlags().String("catalog-bundle", "", "Offline catalog bundle file path.")
+
+	outputUsage := fmt.Sprintf("Output format. (%s)", strings.Join(output.Formats(), ", "))
+	for _, cmd := range []*cobra.Command{infoCmd, eventCmd, waitCmd} {
+		cmd.Flags().String("output", output.FormatText, outputUsage)
+		cmd.Flags().StringSlice("fields", []string{}, "Output fields. (default all fields)")
+	}
+
+	eventCmd.Flags().String("begin", "", "Begin time in RFC3339 (e.g. 2006-01-02T15:04:05+09:00).")
+	eventCmd.Flags().String("end", "", "End time in RFC3339 (e.g. 2006-01-02T15:04:05+09:00).")
+	eventCmd.Flags().Int("max", 0, "Maximum number of events. (0 is unlimited)")
 
 	waitCmd.Flags().Int32("timeout", 60, "Timeout in seconds.")
 
@@ -311,32 +616,107 @@ func init() {
 	lokiCmd.PersistentFlags().String("tenant", "", "Loki tenant.")
 	lokiCmd.PersistentFlags().Bool("loki-no-verify-ssl", false, "Skip SSL verification.")
 	lokiCmd.PersistentFlags().String("loki-service-name", "vmomi-event-source", "Loki service name.")
+	lokiCmd.PersistentFlags().String("loki-user", "", "Loki username.")
+	lokiCmd.PersistentFlags().String("loki-password", "", "Loki password.")
+	lokiCmd.PersistentFlags().String("loki-credential", "", "Loki credential source.")
+	lokiCmd.PersistentFlags().StringSlice("loki-ca-bundle", []string{}, "Loki CA bundle file paths.")
+	lokiCmd.PersistentFlags().String("loki-cert-file", "", "Loki client certificate file path.")
+	lokiCmd.PersistentFlags().String("loki-key-file", "", "Loki client private key file path.")
+	lokiCmd.PersistentFlags().String("loki-tls-min-version", "", "Loki minimum TLS version.")
+	lokiCmd.PersistentFlags().String("loki-proxy", "", "Loki proxy URL.")
+	lokiCmd.PersistentFlags().StringSlice("loki-no-proxy", []string{}, "Hosts not to use Loki proxy.")
+
+	configGenerateCmd.Flags().StringSlice("exclude-categories", []string{}, "Exclude categories.")
 
 	lokiTestCmd.Flags().String("message", "Test message", "Message to send.")
 
-	rootCmd.AddCommand(categoryCmd)
-	rootCmd.AddCommand(configCmd)
-	rootCmd.AddCommand(enumeratedCmd)
-	rootCmd.AddCommand(infoCmd)
-	rootCmd.AddCommand(eventCmd)
-	rootCmd.AddCommand(waitCmd)
-	rootCmd.AddCommand(lokiCmd)
+	lokiCollectCmd.Flags().Int("batch-max-bytes", 1048576, "Max bytes to push in one batch.")
+	lokiCollectCmd.Flags().Int("batch-max-entries", 1000, "Max events to push in one batch.")
+	lokiCollectCmd.Flags().Int("batch-max-wait", 1, "Max seconds to wait batch. (0 is no wait)")
+	lokiCollectCmd.Flags().Int("chain-idle-timeout", 60, "Idle seconds to complete event chain.")
+	lokiCollectCmd.Flags().String("checkpoint", "", "Checkpoint file path.")
+	lokiCollectCmd.Flags().Bool("collect-alarms", false, "Collect triggered alarm state changes.")
+	lokiCollectCmd.Flags().Bool("collect-tasks", false, "Collect completed tasks.")
+	lokiCollectCmd.Flags().Bool("correlate-chains", false, "Collect summary of event chains.")
+	lokiCollectCmd.Flags().Bool("enrich-inventory", false, "Add inventory path to events.")
+	lokiCollectCmd.Flags().StringSlice("message-locales", []string{}, "Add messages in locales.")
 
-	lokiCmd.AddCommand(lokiTestCmd)
-	lokiCmd.AddCommand(lokiCollectCmd)
+	catalogExportCmd.Flags().StringSlice("locales", []string{}, "Export locales. (default session locale)")
+	catalogExportCmd.Flags().String("file", "", "Bundle file path. (default stdout)")
+
+	initCommands()
 
 	viper.BindPFlag("target_url", rootCmd.PersistentFlags().Lookup("url"))
 	viper.BindPFlag("target_user", rootCmd.PersistentFlags().Lookup("user"))
 	viper.BindPFlag("target_password", rootCmd.PersistentFlags().Lookup("password"))
 	viper.BindPFlag("target_no_verify_ssl", rootCmd.PersistentFlags().Lookup("no-verify-ssl"))
 	viper.BindPFlag("target_timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	viper.BindPFlag("target_locale", rootCmd.PersistentFlags().Lookup("locale"))
 	viper.BindPFlag("log_level", rootCmd.Flags().Lookup("log-level"))
 	viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
+	viper.BindPFlag("target_token_file", rootCmd.PersistentFlags().Lookup("token-file"))
+	viper.BindPFlag("target_cert_file", rootCmd.PersistentFlags().Lookup("cert-file"))
+	viper.BindPFlag("target_key_file", rootCmd.PersistentFlags().Lookup("key-file"))
+	viper.BindPFlag("target_session_dir", rootCmd.PersistentFlags().Lookup("session-dir"))
+	viper.BindPFlag("target_credential", rootCmd.PersistentFlags().Lookup("credential"))
+	viper.BindPFlag("credential_key_file", rootCmd.PersistentFlags().Lookup("credential-key-file"))
+	viper.BindPFlag("target_ca_bundle", rootCmd.PersistentFlags().Lookup("ca-bundle"))
+	viper.BindPFlag("target_thumbprint", rootCmd.PersistentFlags().Lookup("thumbprint"))
+	viper.BindPFlag("target_tls_min_version", rootCmd.PersistentFlags().Lookup("tls-min-version"))
+	viper.BindPFlag("target_tls_server_name", rootCmd.PersistentFlags().Lookup("tls-server-name"))
+	viper.BindPFlag("target_proxy", rootCmd.PersistentFlags().Lookup("proxy"))
+	viper.BindPFlag("target_no_proxy", rootCmd.PersistentFlags().Lookup("no-proxy"))
+	viper.BindPFlag("target_catalog_ttl", rootCmd.PersistentFlags().Lookup("catalog-ttl"))
+	viper.BindPFlag("target_catalog_cache_dir", rootCmd.PersistentFlags().Lookup("catalog-cache-dir"))
+	viper.BindPFlag("target_catalog_bundle", rootCmd.PersistentFlags().Lookup("catalog-bundle"))
 
 	viper.BindPFlag("loki_url", lokiCmd.PersistentFlags().Lookup("loki-url"))
 	viper.BindPFlag("loki_tenant", lokiCmd.PersistentFlags().Lookup("tenant"))
 	viper.BindPFlag("loki_no_verify_ssl", lokiCmd.PersistentFlags().Lookup("loki-no-verify-ssl"))
 	viper.BindPFlag("loki_service_name", lokiCmd.PersistentFlags().Lookup("loki-service-name"))
+	viper.BindPFlag("loki_user", lokiCmd.PersistentFlags().Lookup("loki-user"))
+	viper.BindPFlag("loki_password", lokiCmd.PersistentFlags().Lookup("loki-password"))
+	viper.BindPFlag("loki_credential", lokiCmd.PersistentFlags().Lookup("loki-credential"))
+	viper.BindPFlag("loki_ca_bundle", lokiCmd.PersistentFlags().Lookup("loki-ca-bundle"))
+	viper.BindPFlag("loki_cert_file", lokiCmd.PersistentFlags().Lookup("loki-cert-file"))
+	viper.BindPFlag("loki_key_file", lokiCmd.PersistentFlags().Lookup("loki-key-file"))
+	viper.BindPFlag("loki_tls_min_version", lokiCmd.PersistentFlags().Lookup("loki-tls-min-version"))
+	viper.BindPFlag("loki_proxy", lokiCmd.PersistentFlags().Lookup("loki-proxy"))
+	viper.BindPFlag("loki_no_proxy", lokiCmd.PersistentFlags().Lookup("loki-no-proxy"))
+
+	viper.BindPFlag("loki_batch_max_bytes", lokiCollectCmd.Flags().Lookup("batch-max-bytes"))
+	viper.BindPFlag("loki_batch_max_entries", lokiCollectCmd.Flags().Lookup("batch-max-entries"))
+	viper.BindPFlag("loki_batch_max_wait", lokiCollectCmd.Flags().Lookup("batch-max-wait"))
+	viper.BindPFlag("loki_chain_idle_timeout", lokiCollectCmd.Flags().Lookup("chain-idle-timeout"))
+	viper.BindPFlag("loki_checkpoint", lokiCollectCmd.Flags().Lookup("checkpoint"))
+	viper.BindPFlag("loki_collect_alarms", lokiCollectCmd.Flags().Lookup("collect-alarms"))
+	viper.BindPFlag("loki_collect_tasks", lokiCollectCmd.Flags().Lookup("collect-tasks"))
+	viper.BindPFlag("loki_correlate_chains", lokiCollectCmd.Flags().Lookup("correlate-chains"))
+	viper.BindPFlag("loki_enrich_inventory", lokiCollectCmd.Flags().Lookup("enrich-inventory"))
+	viper.BindPFlag("loki_message_locales", lokiCollectCmd.Flags().Lookup("message-locales"))
+}
+
+//revive:enable:function-length
+
+func initCommands() {
+	rootCmd.AddCommand(catalogCmd)
+	rootCmd.AddCommand(categoryCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(credentialCmd)
+	rootCmd.AddCommand(enumeratedCmd)
+	rootCmd.AddCommand(infoCmd)
+	rootCmd.AddCommand(eventCmd)
+	rootCmd.AddCommand(waitCmd)
+	rootCmd.AddCommand(lokiCmd)
+
+	catalogCmd.AddCommand(catalogExportCmd)
+
+	configCmd.AddCommand(configGenerateCmd)
+
+	credentialCmd.AddCommand(credentialEncryptCmd)
+
+	lokiCmd.AddCommand(lokiTestCmd)
+	lokiCmd.AddCommand(lokiCollectCmd)
 }
 
 //revive:enable:add-constant
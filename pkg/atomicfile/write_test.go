@@ -0,0 +1,46 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//revive:disable:add-constant
+
+func testReadFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+
+	for _, data := range []string{"first", "second"} {
+		err := Write(path, []byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actual := testReadFile(t, path); actual != data {
+			t.Errorf("Invalid data: %s", actual)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("Temporary file remains: %v", entries)
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,109 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/atomicfile"
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+type Store struct {
+	path        string
+	mu          sync.Mutex
+	checkpoints map[string]vmomi.Checkpoint
+}
+
+func NewStore(path string) *Store {
+	return &Store{
+		path: path,
+	}
+}
+
+func GetStore(ctx context.Context) *Store {
+	filePath, ok := ctx.Value(flag.LokiCheckpointKey{}).(string)
+	if !ok || filePath == "" {
+		return nil
+	}
+
+	return NewStore(filePath)
+}
+
+func (s *Store) Load(target string) (*vmomi.Checkpoint, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	cp, ok := s.checkpoints[target]
+	if !ok {
+		return nil, nil
+	}
+
+	return &cp, nil
+}
+
+func (s *Store) Save(target string, cp *vmomi.Checkpoint) error {
+	if s == nil || cp == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.read()
+	if err != nil {
+		return err
+	}
+
+	s.checkpoints[target] = *cp
+
+	return s.write()
+}
+
+func (s *Store) read() error {
+	if s.checkpoints != nil {
+		return nil
+	}
+
+	checkpoints := map[string]vmomi.Checkpoint{}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		s.checkpoints = checkpoints
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, &checkpoints)
+	if err != nil {
+		return err
+	}
+
+	s.checkpoints = checkpoints
+	return nil
+}
+
+func (s *Store) write() error {
+	data, err := json.MarshalIndent(s.checkpoints, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(s.path, data)
+}
@@ -0,0 +1,44 @@
+package checkpoint
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+//revive:disable:add-constant
+
+func TestStore_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	cp := vmomi.Checkpoint{
+		Key:         1,
+		CreatedTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	err := NewStore(path).Save("target", &cp)
+	if err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	loaded, err := NewStore(path).Load("target")
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	if loaded == nil || loaded.Key != cp.Key || !loaded.CreatedTime.Equal(cp.CreatedTime) {
+		t.Errorf("Invalid checkpoint: %v", loaded)
+	}
+}
+
+func TestStore_LoadNotExist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	loaded, err := NewStore(path).Load("target")
+	if err != nil || loaded != nil {
+		t.Errorf("Invalid checkpoint: %v %v", loaded, err)
+	}
+}
+
+//revive:enable:add-constant
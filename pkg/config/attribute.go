@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"maps"
+	"strings"
+)
+
+const AnyEventTypeID = "*"
+
+type Attribute struct {
+	EventTypeID string   `yaml:"event_type_id"`
+	Names       []string `yaml:"names,omitempty"`
+}
+
+type AttributeConfig struct {
+	Attributes []Attribute `yaml:"attributes,omitempty"`
+}
+
+func DefaultAttributeConfig() *AttributeConfig {
+	return &AttributeConfig{
+		Attributes: []Attribute{},
+	}
+}
+
+func (c *AttributeConfig) Validate() error {
+	for _, a := range c.Attributes {
+		// All arguments of all events are too large to push.
+		if a.EventTypeID == AnyEventTypeID && len(a.Names) == Empty {
+			return errors.New("attribute names are required for all event types")
+		}
+	}
+
+	return nil
+}
+
+//revive:disable:cognitive-complexity
+
+// SelectAttributes gets attributes only if the event type is configured.
+func (c *AttributeConfig) SelectAttributes(
+	eventTypeID string,
+	getAttributes func() map[string]string,
+) map[string]string {
+	var selected map[string]string
+	var attributes map[string]string
+
+	for _, a := range c.Attributes {
+		if a.EventTypeID != eventTypeID && a.EventTypeID != AnyEventTypeID {
+			continue
+		}
+
+		if selected == nil {
+			selected = map[string]string{}
+			attributes = getAttributes()
+		}
+
+		if len(a.Names) == Empty {
+			maps.Copy(selected, attributes)
+			continue
+		}
+
+		for name, value := range attributes {
+			if matchAttributeName(a.Names, name) {
+				selected[name] = value
+			}
+		}
+	}
+
+	return selected
+}
+
+//revive:enable:cognitive-complexity
+
+func matchAttributeName(names []string, name string) bool {
+	for _, n := range names {
+		// Select nested attributes by parent name.
+		if n == name || strings.HasPrefix(name, n+".") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+)
+
+//revive:disable:add-constant
+
+func testAttributes() map[string]string {
+	return map[string]string{
+		"sourceHost.name": "esxi01",
+		"sourceHost.host": "host-1",
+		"template":        "false",
+	}
+}
+
+func TestAttributeConfig_SelectAttributes_Names(t *testing.T) {
+	c := AttributeConfig{
+		Attributes: []Attribute{
+			{EventTypeID: "VmMigratedEvent", Names: []string{"sourceHost"}},
+		},
+	}
+
+	selected := c.SelectAttributes("VmMigratedEvent", testAttributes)
+	if len(selected) != 2 || selected["sourceHost.name"] != "esxi01" {
+		t.Errorf("Invalid attributes: %v", selected)
+	}
+}
+
+func TestAttributeConfig_SelectAttributes_All(t *testing.T) {
+	c := AttributeConfig{
+		Attributes: []Attribute{
+			{EventTypeID: AnyEventTypeID},
+		},
+	}
+
+	selected := c.SelectAttributes("VmMigratedEvent", testAttributes)
+	if len(selected) != 3 {
+		t.Errorf("Invalid attributes: %v", selected)
+	}
+}
+
+func TestAttributeConfig_SelectAttributes_NotMatch(t *testing.T) {
+	c := AttributeConfig{
+		Attributes: []Attribute{
+			{EventTypeID: "VmPoweredOnEvent"},
+		},
+	}
+
+	selected := c.SelectAttributes("VmMigratedEvent", func() map[string]string {
+		t.Error("Attributes walked for not configured event")
+		return testAttributes()
+	})
+	if selected != nil {
+		t.Errorf("Invalid attributes: %v", selected)
+	}
+}
+
+func TestAttributeConfig_Validate(t *testing.T) {
+	cases := map[string]struct {
+		attribute Attribute
+		valid     bool
+	}{
+		"any": {attribute: Attribute{EventTypeID: AnyEventTypeID}, valid: false},
+		"any names": {
+			attribute: Attribute{EventTypeID: AnyEventTypeID, Names: []string{"ipAddress"}},
+			valid:     true,
+		},
+		"event": {attribute: Attribute{EventTypeID: "VmMigratedEvent"}, valid: true},
+	}
+
+	for name, tc := range cases {
+		c := AttributeConfig{Attributes: []Attribute{tc.attribute}}
+		if err := c.Validate(); (err == nil) != tc.valid {
+			t.Errorf("%s: Invalid result: %v", name, err)
+		}
+	}
+}
+
+//revive:enable:add-constant
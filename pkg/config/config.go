@@ -8,8 +8,16 @@ import (
 	"go.yaml.in/yaml/v4"
 )
 
+const Empty = int(0)
+
 type Config struct {
-	ExcludeConfig `yaml:",omitempty,inline"`
+	AttributeConfig   `yaml:",omitempty,inline"`
+	CustomFieldConfig `yaml:",omitempty,inline"`
+	ExcludeConfig     `yaml:",omitempty,inline"`
+	FilterConfig      `yaml:",omitempty,inline"`
+	SeverityConfig    `yaml:",omitempty,inline"`
+	TagConfig         `yaml:",omitempty,inline"`
+	TargetConfig      `yaml:",omitempty,inline"`
 }
 
 func DecodeConfig(config []byte) (*Config, error) {
@@ -19,6 +27,16 @@ func DecodeConfig(config []byte) (*Config, error) {
 		return nil, err
 	}
 
+	err = c.TargetConfig.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.AttributeConfig.Validate()
+	if err != nil {
+		return nil, err
+	}
+
 	return &c, nil
 }
 
@@ -33,7 +51,13 @@ func EncodeConfig(c *Config) (string, error) {
 
 func DefaultConfig() *Config {
 	return &Config{
-		ExcludeConfig: *DefaultExcludeConfig(),
+		AttributeConfig:   *DefaultAttributeConfig(),
+		CustomFieldConfig: *DefaultCustomFieldConfig(),
+		ExcludeConfig:     *DefaultExcludeConfig(),
+		FilterConfig:      *DefaultFilterConfig(),
+		SeverityConfig:    *DefaultSeverityConfig(),
+		TagConfig:         *DefaultTagConfig(),
+		TargetConfig:      *DefaultTargetConfig(),
 	}
 }
 
@@ -0,0 +1,11 @@
+package config
+
+type CustomFieldConfig struct {
+	CustomFields []string `yaml:"custom_fields,omitempty"`
+}
+
+func DefaultCustomFieldConfig() *CustomFieldConfig {
+	return &CustomFieldConfig{
+		CustomFields: nil,
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"context"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+)
+
+type EntityFilter struct {
+	Path      string `yaml:"path"`
+	Recursion string `yaml:"recursion,omitempty"`
+}
+
+type Filter struct {
+	Entity       *EntityFilter `yaml:"entity,omitempty"`
+	EventTypeIDs []string      `yaml:"event_type_ids,omitempty"`
+	Categories   []string      `yaml:"categories,omitempty"`
+	UserNames    []string      `yaml:"user_names,omitempty"`
+	SystemUser   bool          `yaml:"system_user,omitempty"`
+}
+
+type FilterConfig struct {
+	Filter *Filter `yaml:"filter,omitempty"`
+}
+
+func DefaultFilterConfig() *FilterConfig {
+	return &FilterConfig{
+		Filter: nil,
+	}
+}
+
+// WithFilterContext sets global filter loaded already unless filter of target is set.
+func (c *FilterConfig) WithFilterContext(ctx context.Context) context.Context {
+	if c.Filter == nil {
+		return ctx
+	}
+
+	if _, ok := ctx.Value(flag.TargetFilterKey{}).(*Filter); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, flag.TargetFilterKey{}, c.Filter)
+}
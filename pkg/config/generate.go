@@ -0,0 +1,60 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type EventType struct {
+	EventTypeID string
+	Category    string
+	Description string
+}
+
+// GenerateConfig returns commented YAML to list all event types grouped by category.
+// The event types in excludeCategories are excluded, and the others are commented out.
+func GenerateConfig(eventTypes []EventType, excludeCategories []string) string {
+	sorted := slices.Clone(eventTypes)
+	slices.SortFunc(sorted, func(a, b EventType) int {
+		return cmp.Or(
+			cmp.Compare(a.Category, b.Category),
+			cmp.Compare(a.EventTypeID, b.EventTypeID),
+		)
+	})
+
+	lines := []string{
+		"# Generated by `vmomi-event-source config generate`.",
+		"# Uncomment the event type to exclude, or comment out to collect.",
+		"excludes:",
+	}
+
+	var category *string
+	for _, eventType := range sorted {
+		if category == nil || *category != eventType.Category {
+			lines = append(lines, "", fmt.Sprintf("  # Category: %s", eventType.Category))
+			category = &eventType.Category
+		}
+
+		lines = append(lines, generateExclude(&eventType, excludeCategories))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func generateExclude(eventType *EventType, excludeCategories []string) string {
+	comment := "# "
+	if slices.Contains(excludeCategories, eventType.Category) {
+		comment = ""
+	}
+
+	exclude := fmt.Sprintf("  %s- event_type_id: %s", comment, eventType.EventTypeID)
+
+	description := strings.Join(strings.Fields(eventType.Description), " ")
+	if len(description) != Empty {
+		exclude = fmt.Sprintf("%s # %s", exclude, description)
+	}
+
+	return exclude
+}
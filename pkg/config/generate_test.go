@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+)
+
+//revive:disable:add-constant
+
+func TestGenerateConfig(t *testing.T) {
+	eventTypes := []EventType{
+		{EventTypeID: "VmPoweredOnEvent", Category: "info", Description: "VM powered on"},
+		{EventTypeID: "VmFailedMigrateEvent", Category: "error", Description: "Cannot\nmigrate"},
+		{EventTypeID: "UserLoginSessionEvent", Category: "info", Description: ""},
+	}
+
+	generated := GenerateConfig(eventTypes, []string{"info"})
+
+	expected := `# Generated by ` + "`vmomi-event-source config generate`" + `.
+# Uncomment the event type to exclude, or comment out to collect.
+excludes:
+
+  # Category: error
+  # - event_type_id: VmFailedMigrateEvent # Cannot migrate
+
+  # Category: info
+  - event_type_id: UserLoginSessionEvent
+  - event_type_id: VmPoweredOnEvent # VM powered on
+`
+	if generated != expected {
+		t.Errorf("Invalid config: %v", generated)
+	}
+
+	c, err := DecodeConfig([]byte(generated))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(c.Excludes) != 2 || c.Excludes[0].EventTypeID != "UserLoginSessionEvent" {
+		t.Errorf("Invalid excludes: %v", c.Excludes)
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"path"
+	"slices"
+)
+
+// Levels in Grafana detected level vocabulary.
+const (
+	LevelCritical = "critical"
+	LevelError    = "error"
+	LevelWarn     = "warn"
+	LevelInfo     = "info"
+	LevelDebug    = "debug"
+	LevelTrace    = "trace"
+	LevelUnknown  = "unknown"
+)
+
+var levels = []string{
+	LevelCritical,
+	LevelError,
+	LevelWarn,
+	LevelInfo,
+	LevelDebug,
+	LevelTrace,
+	LevelUnknown,
+}
+
+// Levels of vSphere categories and common aliases.
+var severityLevels = map[string]string{
+	"fatal":   LevelCritical,
+	"warning": LevelWarn,
+	"user":    LevelInfo,
+}
+
+type SeverityOverride struct {
+	EventTypeID string `yaml:"event_type_id,omitempty"`
+	TaskTypeID  string `yaml:"task_type_id,omitempty"`
+	AlarmName   string `yaml:"alarm_name,omitempty"`
+	Severity    string `yaml:"severity"`
+}
+
+type Severity struct {
+	Overrides []SeverityOverride `yaml:"overrides,omitempty"`
+	Mappings  map[string]string  `yaml:"mappings,omitempty"`
+	Level     bool               `yaml:"level,omitempty"`
+}
+
+type SeverityConfig struct {
+	Severity *Severity `yaml:"severity,omitempty"`
+}
+
+func DefaultSeverityConfig() *SeverityConfig {
+	return &SeverityConfig{
+		Severity: nil,
+	}
+}
+
+// GetSeverity returns the severity overridden by event type ID,
+// or mapped from vSphere category.
+func (c *SeverityConfig) GetSeverity(eventTypeID string, category string) string {
+	return c.findSeverity(eventTypeID, category, func(o SeverityOverride) string {
+		return o.EventTypeID
+	})
+}
+
+// GetTaskSeverity returns the severity overridden by task type ID,
+// or mapped from vSphere category.
+func (c *SeverityConfig) GetTaskSeverity(taskTypeID string, category string) string {
+	return c.findSeverity(taskTypeID, category, func(o SeverityOverride) string {
+		return o.TaskTypeID
+	})
+}
+
+// GetAlarmSeverity returns the severity overridden by alarm name,
+// or mapped from vSphere category.
+func (c *SeverityConfig) GetAlarmSeverity(alarmName string, category string) string {
+	return c.findSeverity(alarmName, category, func(o SeverityOverride) string {
+		return o.AlarmName
+	})
+}
+
+func (c *SeverityConfig) findSeverity(
+	id string,
+	category string,
+	key func(o SeverityOverride) string,
+) string {
+	if c.Severity == nil {
+		return category
+	}
+
+	override := c.Severity.findOverride(id, key)
+	if override != nil {
+		return *override
+	}
+
+	mapped, ok := c.Severity.Mappings[category]
+	if ok {
+		return mapped
+	}
+
+	return category
+}
+
+// GetLevel returns Grafana detected level of severity if enabled.
+func (c *SeverityConfig) GetLevel(severity string) *string {
+	if c.Severity == nil || !c.Severity.Level {
+		return nil
+	}
+
+	level := ToLevel(severity)
+	return &level
+}
+
+func (s *Severity) findOverride(id string, key func(o SeverityOverride) string) *string {
+	// Exact match takes precedence over glob.
+	idx := slices.IndexFunc(s.Overrides, func(o SeverityOverride) bool {
+		return len(key(o)) != Empty && key(o) == id
+	})
+	if idx >= Empty {
+		return &s.Overrides[idx].Severity
+	}
+
+	idx = slices.IndexFunc(s.Overrides, func(o SeverityOverride) bool {
+		matched, err := path.Match(key(o), id)
+		return len(key(o)) != Empty && err == nil && matched
+	})
+	if idx >= Empty {
+		return &s.Overrides[idx].Severity
+	}
+
+	return nil
+}
+
+// ToLevel returns Grafana detected level of severity.
+func ToLevel(severity string) string {
+	if slices.Contains(levels, severity) {
+		return severity
+	}
+
+	level, ok := severityLevels[severity]
+	if ok {
+		return level
+	}
+
+	return LevelUnknown
+}
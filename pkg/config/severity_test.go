@@ -0,0 +1,112 @@
+package config
+
+import (
+	"testing"
+)
+
+//revive:disable:add-constant
+
+func testSeverityConfig() SeverityConfig {
+	return SeverityConfig{
+		Severity: &Severity{
+			Overrides: []SeverityOverride{
+				{EventTypeID: "com.vmware.vc.HA.*", Severity: "warning"},
+				{EventTypeID: "com.vmware.vc.HA.HostFailedEvent", Severity: "critical"},
+				{TaskTypeID: "VirtualMachine.*", Severity: "warning"},
+				{AlarmName: "Host connection*", Severity: "critical"},
+			},
+			Mappings: map[string]string{"user": "info"},
+			Level:    true,
+		},
+	}
+}
+
+func TestSeverityConfig_GetSeverity_Override(t *testing.T) {
+	c := testSeverityConfig()
+
+	severity := c.GetSeverity("com.vmware.vc.HA.HostFailedEvent", "info")
+	if severity != "critical" {
+		t.Errorf("Invalid severity: %v", severity)
+	}
+
+	severity = c.GetSeverity("com.vmware.vc.HA.ClusterFailoverActionInitiatedEvent", "info")
+	if severity != "warning" {
+		t.Errorf("Invalid severity: %v", severity)
+	}
+}
+
+func TestSeverityConfig_GetSeverity_Mapping(t *testing.T) {
+	c := testSeverityConfig()
+
+	severity := c.GetSeverity("UserLoginSessionEvent", "user")
+	if severity != "info" {
+		t.Errorf("Invalid severity: %v", severity)
+	}
+
+	severity = c.GetSeverity("VmFailedMigrateEvent", "error")
+	if severity != "error" {
+		t.Errorf("Invalid severity: %v", severity)
+	}
+}
+
+func TestSeverityConfig_GetTaskSeverity_Override(t *testing.T) {
+	c := testSeverityConfig()
+
+	severity := c.GetTaskSeverity("VirtualMachine.powerOn", "info")
+	if severity != "warning" {
+		t.Errorf("Invalid severity: %v", severity)
+	}
+
+	severity = c.GetSeverity("VirtualMachine.powerOn", "info")
+	if severity != "info" {
+		t.Errorf("Invalid severity: %v", severity)
+	}
+}
+
+func TestSeverityConfig_GetAlarmSeverity_Override(t *testing.T) {
+	c := testSeverityConfig()
+
+	severity := c.GetAlarmSeverity("Host connection and power state", "error")
+	if severity != "critical" {
+		t.Errorf("Invalid severity: %v", severity)
+	}
+
+	severity = c.GetAlarmSeverity("alarm-12", "user")
+	if severity != "info" {
+		t.Errorf("Invalid severity: %v", severity)
+	}
+}
+
+func TestSeverityConfig_GetLevel(t *testing.T) {
+	c := testSeverityConfig()
+
+	levels := map[string]string{
+		"critical": LevelCritical,
+		"warning":  LevelWarn,
+		"user":     LevelInfo,
+		"custom":   LevelUnknown,
+	}
+
+	for severity, expected := range levels {
+		level := c.GetLevel(severity)
+		if level == nil || *level != expected {
+			t.Errorf("Invalid level: %v, %v", severity, level)
+		}
+	}
+}
+
+func TestSeverityConfig_GetLevel_Disabled(t *testing.T) {
+	c := DefaultSeverityConfig()
+
+	level := c.GetLevel("info")
+	if level != nil {
+		t.Errorf("Invalid level: %v", level)
+	}
+
+	severity := c.GetSeverity("VmPoweredOnEvent", "info")
+	if severity != "info" {
+		t.Errorf("Invalid severity: %v", severity)
+	}
+}
+
+//revive:enable:add-constant
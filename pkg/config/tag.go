@@ -0,0 +1,27 @@
+package config
+
+const DefaultTagRefreshSeconds = 300
+
+type Tags struct {
+	Categories     []string `yaml:"categories"`
+	Labels         []string `yaml:"labels,omitempty"`
+	RefreshSeconds int      `yaml:"refresh_seconds,omitempty"`
+}
+
+type TagConfig struct {
+	Tags *Tags `yaml:"tags,omitempty"`
+}
+
+func DefaultTagConfig() *TagConfig {
+	return &TagConfig{
+		Tags: nil,
+	}
+}
+
+func (t *Tags) GetRefreshSeconds() int {
+	if t.RefreshSeconds <= Empty {
+		return DefaultTagRefreshSeconds
+	}
+
+	return t.RefreshSeconds
+}
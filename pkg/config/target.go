@@ -0,0 +1,159 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/vim25/soap"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+)
+
+type Target struct {
+	Name        string `yaml:"name,omitempty"`
+	URL         string `yaml:"url"`
+	User        string `yaml:"user,omitempty"`
+	Password    string `yaml:"password,omitempty"`
+	NoVerifySSL *bool  `yaml:"no_verify_ssl,omitempty"`
+	Locale      string `yaml:"locale,omitempty"`
+	TokenFile   string `yaml:"token_file,omitempty"`
+	CertFile    string `yaml:"cert_file,omitempty"`
+	KeyFile     string `yaml:"key_file,omitempty"`
+	Credential  string `yaml:"credential,omitempty"`
+	// Thumbprint and CA bundle differ per vCenter.
+	CABundle      []string `yaml:"ca_bundle,omitempty"`
+	Thumbprint    string   `yaml:"thumbprint,omitempty"`
+	TLSMinVersion string   `yaml:"tls_min_version,omitempty"`
+	TLSServerName string   `yaml:"tls_server_name,omitempty"`
+	Proxy         string   `yaml:"proxy,omitempty"`
+	NoProxy       []string `yaml:"no_proxy,omitempty"`
+	// Filter replaces global filter for the vCenter.
+	Filter *Filter `yaml:"filter,omitempty"`
+}
+
+type TargetConfig struct {
+	Targets []Target `yaml:"targets,omitempty"`
+}
+
+func DefaultTargetConfig() *TargetConfig {
+	return &TargetConfig{
+		Targets: []Target{},
+	}
+}
+
+// Validate rejects targets sharing name, because name is key of checkpoint and label.
+func (c *TargetConfig) Validate() error {
+	names := map[string]bool{}
+	for _, target := range c.Targets {
+		name := target.GetName()
+		if len(name) == Empty {
+			return fmt.Errorf("target name is empty: url=%q", target.URL)
+		}
+
+		if names[name] {
+			return fmt.Errorf("target name is duplicated: %s", name)
+		}
+
+		names[name] = true
+	}
+
+	return nil
+}
+
+// GetName returns name, or host in URL if name is empty.
+func (t *Target) GetName() string {
+	if len(t.Name) != Empty {
+		return t.Name
+	}
+
+	return GetHostName(t.URL)
+}
+
+func GetHostName(endpoint string) string {
+	u, err := soap.ParseURL(endpoint)
+	if err != nil || u == nil {
+		return endpoint
+	}
+
+	return u.Hostname()
+}
+
+func (t *Target) WithContext(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, flag.TargetNameKey{}, t.GetName())
+	ctx = context.WithValue(ctx, flag.TargetURLKey{}, t.URL)
+
+	// Inherit from arguments if empty.
+
+	if t.User != "" {
+		ctx = context.WithValue(ctx, flag.TargetUserKey{}, t.User)
+	}
+
+	if t.Password != "" {
+		ctx = context.WithValue(ctx, flag.TargetPasswordKey{}, t.Password)
+	}
+
+	if t.NoVerifySSL != nil {
+		ctx = context.WithValue(ctx, flag.TargetNoVerifySSLKey{}, *t.NoVerifySSL)
+	}
+
+	if len(t.Locale) != Empty {
+		ctx = context.WithValue(ctx, flag.TargetLocaleKey{}, t.Locale)
+	}
+
+	if t.Filter != nil {
+		ctx = context.WithValue(ctx, flag.TargetFilterKey{}, t.Filter)
+	}
+
+	ctx = t.withAuthContext(ctx)
+	ctx = t.withProxyContext(ctx)
+	return t.withTLSContext(ctx)
+}
+
+func (t *Target) withAuthContext(ctx context.Context) context.Context {
+	if len(t.TokenFile) != Empty {
+		ctx = context.WithValue(ctx, flag.TargetTokenFileKey{}, t.TokenFile)
+	}
+
+	if len(t.CertFile) != Empty {
+		ctx = context.WithValue(ctx, flag.TargetCertFileKey{}, t.CertFile)
+		ctx = context.WithValue(ctx, flag.TargetKeyFileKey{}, t.KeyFile)
+	}
+
+	if len(t.Credential) != Empty {
+		ctx = context.WithValue(ctx, flag.TargetCredentialKey{}, t.Credential)
+	}
+
+	return ctx
+}
+
+func (t *Target) withProxyContext(ctx context.Context) context.Context {
+	if len(t.Proxy) != Empty {
+		ctx = context.WithValue(ctx, flag.TargetProxyKey{}, t.Proxy)
+	}
+
+	if len(t.NoProxy) != Empty {
+		ctx = context.WithValue(ctx, flag.TargetNoProxyKey{}, t.NoProxy)
+	}
+
+	return ctx
+}
+
+func (t *Target) withTLSContext(ctx context.Context) context.Context {
+	if len(t.CABundle) != Empty {
+		ctx = context.WithValue(ctx, flag.TargetCABundleKey{}, t.CABundle)
+	}
+
+	if len(t.Thumbprint) != Empty {
+		ctx = context.WithValue(ctx, flag.TargetThumbprintKey{}, t.Thumbprint)
+	}
+
+	if len(t.TLSMinVersion) != Empty {
+		ctx = context.WithValue(ctx, flag.TargetTLSMinVersionKey{}, t.TLSMinVersion)
+	}
+
+	if len(t.TLSServerName) != Empty {
+		ctx = context.WithValue(ctx, flag.TargetTLSServerNameKey{}, t.TLSServerName)
+	}
+
+	return ctx
+}
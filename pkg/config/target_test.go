@@ -0,0 +1,157 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+)
+
+//revive:disable:add-constant
+
+func TestDecodeConfig_Targets(t *testing.T) {
+	c, err := DecodeConfig([]byte(`
+targets:
+  - name: vcenter01
+    url: https://vcenter01.example.com/sdk
+  - url: https://vcenter02.example.com/sdk
+    user: administrator@vsphere.local
+  - url: vcenter03
+`))
+	if err != nil {
+		t.Fatalf("DecodeConfig error: %v", err)
+	}
+
+	names := []string{"vcenter01", "vcenter02.example.com", "vcenter03"}
+	if len(c.Targets) != len(names) {
+		t.Fatalf("Invalid targets: %v", c.Targets)
+	}
+
+	for i, target := range c.Targets {
+		name, ok := target.WithContext(t.Context()).Value(flag.TargetNameKey{}).(string)
+		if !ok || name != names[i] {
+			t.Errorf("Invalid name: %v", name)
+		}
+	}
+}
+
+func TestTarget_WithContext_Filter(t *testing.T) {
+	c, err := DecodeConfig([]byte(`
+targets:
+  - url: https://vcenter01.example.com/sdk
+    filter:
+      categories:
+        - error
+  - url: https://vcenter02.example.com/sdk
+`))
+	if err != nil {
+		t.Fatalf("DecodeConfig error: %v", err)
+	}
+
+	f, ok := c.Targets[0].WithContext(t.Context()).Value(flag.TargetFilterKey{}).(*Filter)
+	if !ok || len(f.Categories) != 1 || f.Categories[0] != "error" {
+		t.Errorf("Invalid filter: %v", f)
+	}
+
+	_, ok = c.Targets[1].WithContext(t.Context()).Value(flag.TargetFilterKey{}).(*Filter)
+	if ok {
+		t.Error("Filter set to target without filter")
+	}
+}
+
+func TestTarget_WithContext_TLS(t *testing.T) {
+	c, err := DecodeConfig([]byte(`
+targets:
+  - url: https://vcenter01.example.com/sdk
+    tls_min_version: "1.3"
+    tls_server_name: vcenter01
+`))
+	if err != nil {
+		t.Fatalf("DecodeConfig error: %v", err)
+	}
+
+	ctx := c.Targets[0].WithContext(t.Context())
+
+	version, ok := ctx.Value(flag.TargetTLSMinVersionKey{}).(string)
+	if !ok || version != "1.3" {
+		t.Errorf("Invalid TLS min version: %v", version)
+	}
+
+	serverName, ok := ctx.Value(flag.TargetTLSServerNameKey{}).(string)
+	if !ok || serverName != "vcenter01" {
+		t.Errorf("Invalid TLS server name: %v", serverName)
+	}
+}
+
+func TestConfig_WithFilterContext(t *testing.T) {
+	c, err := DecodeConfig([]byte(`
+filter:
+  categories:
+    - warning
+targets:
+  - url: https://vcenter01.example.com/sdk
+    filter:
+      categories:
+        - error
+  - url: https://vcenter02.example.com/sdk
+`))
+	if err != nil {
+		t.Fatalf("DecodeConfig error: %v", err)
+	}
+
+	expected := []string{"error", "warning"}
+	for i, target := range c.Targets {
+		ctx := c.WithFilterContext(target.WithContext(t.Context()))
+
+		f, ok := ctx.Value(flag.TargetFilterKey{}).(*Filter)
+		if !ok || len(f.Categories) != 1 || f.Categories[0] != expected[i] {
+			t.Errorf("Invalid filter: %v", f)
+		}
+	}
+}
+
+func TestDecodeConfig_TargetsDuplicated(t *testing.T) {
+	_, err := DecodeConfig([]byte(`
+targets:
+  - name: vcenter01
+    url: https://vcenter01.example.com/sdk
+  - name: vcenter01
+    url: https://vcenter02.example.com/sdk
+`))
+	if err == nil {
+		t.Error("Duplicated name accepted")
+	}
+}
+
+func TestTargetConfig_Validate_SameHost(t *testing.T) {
+	c := TargetConfig{
+		Targets: []Target{
+			{URL: "https://vcenter01.example.com/sdk"},
+			{URL: "https://vcenter01.example.com:8443/sdk"},
+		},
+	}
+
+	err := c.Validate()
+	if err == nil {
+		t.Error("Duplicated host accepted")
+	}
+
+	c.Targets[1].Name = "vcenter01-8443"
+
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("Validate error: %v", err)
+	}
+}
+
+func TestTargetConfig_Validate_Empty(t *testing.T) {
+	c := TargetConfig{
+		Targets: []Target{{User: "administrator@vsphere.local"}},
+	}
+
+	err := c.Validate()
+	if err == nil {
+		t.Error("Empty name accepted")
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,118 @@
+package credential
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const Empty = int(0)
+
+const (
+	SourceFile      = "file"
+	SourceExec      = "exec"
+	SourceEncrypted = "encrypted"
+)
+
+type Credential struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// Provider returns the credential which may be rotated while running.
+type Provider interface {
+	Get(ctx context.Context) (*Credential, error)
+}
+
+type providerKey struct {
+	source  string
+	keyFile string
+}
+
+// Providers are shared to keep cached credential between login.
+var providers sync.Map
+
+// GetProvider returns the provider for source (e.g. `file:/run/secrets/password`).
+func GetProvider(source string, keyFile string) (Provider, error) {
+	key := providerKey{source: source, keyFile: keyFile}
+
+	cached, ok := providers.Load(key)
+	if ok {
+		provider, ok := cached.(Provider)
+		if ok {
+			return provider, nil
+		}
+	}
+
+	provider, err := NewProvider(source, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, _ = providers.LoadOrStore(key, provider)
+
+	provider, ok = cached.(Provider)
+	if !ok {
+		return nil, fmt.Errorf("invalid credential provider: %s", source)
+	}
+
+	return provider, nil
+}
+
+func NewProvider(source string, keyFile string) (Provider, error) {
+	scheme, value, ok := strings.Cut(source, ":")
+	if !ok || len(value) == Empty {
+		return nil, fmt.Errorf("invalid credential source: %s", source)
+	}
+
+	switch scheme {
+	case SourceFile:
+		return NewFileProvider(value), nil
+	case SourceExec:
+		return NewExecProvider(strings.Fields(value)), nil
+	case SourceEncrypted:
+		return NewEncryptedFileProvider(value, keyFile), nil
+	default:
+		return nil, fmt.Errorf("unknown credential source: %s", scheme)
+	}
+}
+
+// Resolve returns the credential from source, or username and password as is.
+// The username and password are used if the credential does not contain them.
+func Resolve(
+	ctx context.Context,
+	source string,
+	keyFile string,
+	username string,
+	password string,
+) (*Credential, error) {
+	resolved := Credential{
+		Username: username,
+		Password: password,
+	}
+
+	if len(source) == Empty {
+		return &resolved, nil
+	}
+
+	provider, err := GetProvider(source, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := provider.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.Username) != Empty {
+		resolved.Username = c.Username
+	}
+
+	if len(c.Password) != Empty {
+		resolved.Password = c.Password
+	}
+
+	return &resolved, nil
+}
@@ -0,0 +1,159 @@
+package credential
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+//revive:disable:add-constant
+
+func testWriteFile(t *testing.T, path string, data string, modTime time.Time) {
+	t.Helper()
+
+	err := os.WriteFile(path, []byte(data), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	err = os.Chtimes(path, modTime, modTime)
+	if err != nil {
+		t.Fatalf("Chtimes error: %v", err)
+	}
+}
+
+func testKeyFile(t *testing.T) string {
+	t.Helper()
+
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", KeySize)))
+	path := filepath.Join(t.TempDir(), "key")
+	testWriteFile(t, path, key, time.Now())
+	return path
+}
+
+func TestFileProvider_Get_Rotated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	modTime := time.Now().Add(-time.Hour)
+	testWriteFile(t, path, "pass1\n", modTime)
+
+	p := NewFileProvider(path)
+
+	c, err := p.Get(t.Context())
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+
+	if c.Password != "pass1" {
+		t.Errorf("Invalid password: %s", c.Password)
+	}
+
+	testWriteFile(t, path, "pass2\n", modTime.Add(time.Minute))
+
+	c, err = p.Get(t.Context())
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+
+	if c.Password != "pass2" {
+		t.Errorf("Invalid password: %s", c.Password)
+	}
+}
+
+func TestExecProvider_Get(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "helper.sh")
+	script := "#!/bin/sh\necho '{\"username\": \"user\", \"password\": \"pass\"}'\n"
+
+	err := os.WriteFile(path, []byte(script), 0o700)
+	if err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	p, err := NewProvider("exec:"+path, "")
+	if err != nil {
+		t.Fatalf("NewProvider error: %v", err)
+	}
+
+	c, err := p.Get(t.Context())
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+
+	if c.Username != "user" || c.Password != "pass" {
+		t.Errorf("Invalid credential: %v", c)
+	}
+}
+
+func TestEncryptedFileProvider_Get(t *testing.T) {
+	keyFile := testKeyFile(t)
+
+	data, err := Encrypt(&Credential{Username: "user", Password: "pass"}, keyFile)
+	if err != nil {
+		t.Fatalf("Encrypt error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "credential")
+	testWriteFile(t, path, string(data), time.Now())
+
+	c, err := NewEncryptedFileProvider(path, keyFile).Get(t.Context())
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+
+	if c.Username != "user" || c.Password != "pass" {
+		t.Errorf("Invalid credential: %v", c)
+	}
+}
+
+func TestEncryptedFileProvider_Get_InvalidKey(t *testing.T) {
+	data, err := Encrypt(&Credential{Password: "pass"}, testKeyFile(t))
+	if err != nil {
+		t.Fatalf("Encrypt error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "credential")
+	testWriteFile(t, path, string(data), time.Now())
+
+	keyFile := filepath.Join(t.TempDir(), "key")
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", KeySize)))
+	testWriteFile(t, keyFile, key, time.Now())
+
+	_, err = NewEncryptedFileProvider(path, keyFile).Get(t.Context())
+	if err == nil {
+		t.Error("Decrypted by invalid key")
+	}
+}
+
+func TestResolve_Fallback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	testWriteFile(t, path, "secret", time.Now())
+
+	c, err := Resolve(t.Context(), "file:"+path, "", "user", "pass")
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+
+	if c.Username != "user" || c.Password != "secret" {
+		t.Errorf("Invalid credential: %v", c)
+	}
+
+	c, err = Resolve(t.Context(), "", "", "user", "pass")
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+
+	if c.Username != "user" || c.Password != "pass" {
+		t.Errorf("Invalid credential: %v", c)
+	}
+}
+
+func TestNewProvider_Unknown(t *testing.T) {
+	_, err := NewProvider("vault:secret/vcenter", "")
+	if err == nil {
+		t.Error("Unknown source accepted")
+	}
+}
+
+//revive:enable:add-constant
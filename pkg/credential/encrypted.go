@@ -0,0 +1,118 @@
+package credential
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+)
+
+// KeySize is size of AES-256 key.
+const KeySize = 32
+
+// EncryptedFileProvider reads credential encrypted by AES-256-GCM.
+// The file is base64 encoded nonce and cipher text of credential in JSON,
+// and the key file is base64 encoded 32 bytes key.
+type EncryptedFileProvider struct {
+	cache fileCache
+}
+
+func NewEncryptedFileProvider(path string, keyFile string) *EncryptedFileProvider {
+	return &EncryptedFileProvider{
+		cache: fileCache{
+			path: path,
+			parse: func(data []byte) (*Credential, error) {
+				return decrypt(data, keyFile)
+			},
+		},
+	}
+}
+
+func (p *EncryptedFileProvider) Get(_ context.Context) (*Credential, error) {
+	return p.cache.Get()
+}
+
+// Encrypt returns credential encrypted by key in key file.
+func Encrypt(c *Credential, keyFile string) ([]byte, error) {
+	aead, err := newAEAD(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := aead.Seal(nonce, nonce, plain, nil)
+	return []byte(base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+func decrypt(data []byte, keyFile string) (*Credential, error) {
+	aead, err := newAEAD(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("invalid encrypted credential")
+	}
+
+	nonce, text := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plain, err := aead.Open(nil, nonce, text, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Credential
+	err = json.Unmarshal(plain, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func newAEAD(keyFile string) (cipher.AEAD, error) {
+	if len(keyFile) == Empty {
+		return nil, errors.New("credential key file not specified")
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) != KeySize {
+		return nil, errors.New("invalid credential key size")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,53 @@
+package credential
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ExecProvider runs external helper which prints credential in JSON
+// (e.g. `{"username": "user", "password": "pass"}`) on every login.
+type ExecProvider struct {
+	command []string
+}
+
+func NewExecProvider(command []string) *ExecProvider {
+	return &ExecProvider{
+		command: command,
+	}
+}
+
+func (p *ExecProvider) Get(ctx context.Context) (*Credential, error) {
+	if len(p.command) == Empty {
+		return nil, errors.New("credential helper not specified")
+	}
+
+	//revive:disable:add-constant
+	cmd := exec.CommandContext(ctx, p.command[0], p.command[1:]...)
+	//revive:enable:add-constant
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to run credential helper: %w: %s",
+			err,
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+
+	var c Credential
+	err = json.Unmarshal(stdout, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
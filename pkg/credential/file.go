@@ -0,0 +1,75 @@
+package credential
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileCache re-reads file if modified.
+type fileCache struct {
+	mu         sync.Mutex
+	path       string
+	modTime    time.Time
+	size       int64
+	credential *Credential
+	parse      func(data []byte) (*Credential, error)
+}
+
+func (f *fileCache) Get() (*Credential, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.credential != nil && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		c := *f.credential
+		return &c, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := f.parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	f.credential = c
+
+	cc := *c
+	return &cc, nil
+}
+
+// FileProvider reads password from file (e.g. Docker or Kubernetes secrets).
+type FileProvider struct {
+	cache fileCache
+}
+
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{
+		cache: fileCache{
+			path:  path,
+			parse: parsePassword,
+		},
+	}
+}
+
+func (p *FileProvider) Get(_ context.Context) (*Credential, error) {
+	return p.cache.Get()
+}
+
+func parsePassword(data []byte) (*Credential, error) {
+	return &Credential{
+		Password: strings.TrimRight(string(data), "\r\n"),
+	}, nil
+}
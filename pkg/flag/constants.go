@@ -2,16 +2,53 @@
 
 package flag
 
+type TargetNameKey struct{}
 type TargetURLKey struct{}
 type TargetUserKey struct{}
 type TargetPasswordKey struct{}
 type TargetNoVerifySSLKey struct{}
 type TargetTimeoutKey struct{}
+type TargetLocaleKey struct{}
+type TargetTokenFileKey struct{}
+type TargetCertFileKey struct{}
+type TargetKeyFileKey struct{}
+type TargetSessionDirKey struct{}
+type TargetCredentialKey struct{}
+type TargetCABundleKey struct{}
+type TargetThumbprintKey struct{}
+type TargetTLSMinVersionKey struct{}
+type TargetTLSServerNameKey struct{}
+type TargetProxyKey struct{}
+type TargetNoProxyKey struct{}
+type TargetFilterKey struct{}
+type TargetCatalogTTLKey struct{}
+type TargetCatalogCacheDirKey struct{}
+type TargetCatalogBundleKey struct{}
+type LokiChainIdleTimeoutKey struct{}
+type LokiCheckpointKey struct{}
+type LokiCollectAlarmsKey struct{}
+type LokiCollectTasksKey struct{}
+type LokiBatchMaxBytesKey struct{}
+type LokiBatchMaxEntriesKey struct{}
+type LokiBatchMaxWaitKey struct{}
+type LokiCABundleKey struct{}
+type LokiCertFileKey struct{}
 type LokiConfigKey struct{}
+type LokiCorrelateChainsKey struct{}
+type LokiCredentialKey struct{}
+type LokiEnrichInventoryKey struct{}
+type LokiKeyFileKey struct{}
+type LokiMessageLocalesKey struct{}
+type LokiNoProxyKey struct{}
 type LokiNoVerifySSLKey struct{}
+type LokiPasswordKey struct{}
+type LokiProxyKey struct{}
 type LokiServiceNameKey struct{}
 type LokiURLKey struct{}
 type LokiTenantIDKey struct{}
+type LokiTLSMinVersionKey struct{}
+type LokiUserKey struct{}
+type CredentialKeyFileKey struct{}
 type LogLevelKey struct{}
 
 //revive:enable:max-public-structs
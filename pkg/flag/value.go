@@ -0,0 +1,25 @@
+package flag
+
+import (
+	"context"
+)
+
+// OptionalValue returns the value of key, or empty if not specified.
+func OptionalValue(ctx context.Context, key any) string {
+	var value string
+	if v, ok := ctx.Value(key).(string); ok {
+		value = v
+	}
+
+	return value
+}
+
+// OptionalValues returns the values of key, or empty if not specified.
+func OptionalValues(ctx context.Context, key any) []string {
+	var values []string
+	if v, ok := ctx.Value(key).([]string); ok {
+		values = v
+	}
+
+	return values
+}
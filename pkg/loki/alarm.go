@@ -0,0 +1,201 @@
+package loki
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+func CollectAlarms(
+	ctx context.Context,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+	batcher *Batcher,
+) {
+	queue := retryQueue[vmomi.Alarm]{}
+
+	for {
+		ch := make(chan *[]vmomi.Alarm)
+
+		go WatchAlarms(ctx, ch)
+
+		NotifyAlarms(ctx, ch, serviceName, vcenter, cfg, batcher, &queue)
+
+		// Retry after 3 seconds
+		time.Sleep(time.Duration(3) * time.Second)
+	}
+}
+
+func WatchAlarms(ctx context.Context, ch chan<- *[]vmomi.Alarm) {
+	err := vmomi.PollAlarms(ctx, nil, ch)
+	if err != nil {
+		warn(ctx, "Failed to poll alarms", err)
+	}
+}
+
+func NotifyAlarms(
+	ctx context.Context,
+	ch <-chan *[]vmomi.Alarm,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+	batcher *Batcher,
+	queue *retryQueue[vmomi.Alarm],
+) {
+	// Push the alarms failed to push again every second.
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case alarms, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			pushAlarms(ctx, queue.take(*alarms), serviceName, vcenter, cfg, batcher, queue)
+		case <-ticker.C:
+			pushAlarms(ctx, queue.take(nil), serviceName, vcenter, cfg, batcher, queue)
+		}
+	}
+}
+
+func pushAlarms(
+	ctx context.Context,
+	alarms []vmomi.Alarm,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+	batcher *Batcher,
+	queue *retryQueue[vmomi.Alarm],
+) {
+	if len(alarms) == Empty {
+		return
+	}
+
+	message := AlarmsToMessage(&alarms, serviceName, vcenter, cfg)
+	batcher.Add(ctx, message.Streams, queue.requeue(alarms))
+}
+
+func AlarmsToMessage(
+	alarms *[]vmomi.Alarm,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+) *Message {
+	streams := make([]*Stream, len(*alarms))
+	for i, alarm := range *alarms {
+		streams[i] = AlarmToStream(&alarm, serviceName, vcenter, cfg)
+	}
+
+	return &Message{
+		Streams: streams,
+	}
+}
+
+func AlarmToStream(
+	alarm *vmomi.Alarm,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+) *Stream {
+	severity := cfg.GetAlarmSeverity(alarm.Name, alarm.Severity)
+
+	return &Stream{
+		Labels: fmt.Sprintf(
+			`{service_name=%q, severity=%q, vcenter=%q, kind="alarm"%s}`,
+			serviceName,
+			severity,
+			vcenter,
+			CreateLevelLabel(severity, &cfg.SeverityConfig),
+		),
+		Entries: []*Entry{
+			{
+				Timestamp:          timestamppb.New(alarm.Time),
+				Line:               getAlarmLine(alarm),
+				StructuredMetadata: CreateAlarmMetadata(alarm),
+			},
+		},
+	}
+}
+
+func CreateAlarmMetadata(alarm *vmomi.Alarm) []*Metadata {
+	//revive:disable:add-constant
+	metadata := make([]*Metadata, 0, 9)
+	//revive:enable:add-constant
+
+	metadata = append(metadata, &Metadata{
+		Name:  "alarm_key",
+		Value: alarm.Key,
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "alarm_id",
+		Value: alarm.AlarmID,
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "alarm_name",
+		Value: alarm.Name,
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "entity",
+		Value: alarm.Entity,
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "entity_id",
+		Value: alarm.EntityID,
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "old_status",
+		Value: alarm.PreviousStatus,
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "new_status",
+		Value: alarm.Status,
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "acknowledged",
+		Value: strconv.FormatBool(alarm.Acknowledged),
+	})
+
+	if alarm.AcknowledgedBy != nil {
+		metadata = append(metadata, &Metadata{
+			Name:  "acknowledged_by",
+			Value: *alarm.AcknowledgedBy,
+		})
+	}
+
+	return metadata
+}
+
+func getAlarmLine(alarm *vmomi.Alarm) string {
+	if alarm.PreviousStatus == alarm.Status && alarm.AcknowledgedBy != nil {
+		return fmt.Sprintf(
+			"Alarm '%s' on %s acknowledged by %s",
+			alarm.Name,
+			alarm.Entity,
+			*alarm.AcknowledgedBy,
+		)
+	}
+
+	return fmt.Sprintf(
+		"Alarm '%s' on %s changed from %s to %s",
+		alarm.Name,
+		alarm.Entity,
+		alarm.PreviousStatus,
+		alarm.Status,
+	)
+}
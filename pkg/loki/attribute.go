@@ -0,0 +1,60 @@
+package loki
+
+import (
+	"sort"
+	"unicode"
+)
+
+const AttributeMetadataPrefix = "arg_"
+
+func CreateAttributeMetadata(attributes map[string]string) []*Metadata {
+	names := make([]string, Empty, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	metadata := make([]*Metadata, len(names))
+	for i, name := range names {
+		metadata[i] = &Metadata{
+			Name:  AttributeMetadataPrefix + toMetadataName(name),
+			Value: attributes[name],
+		}
+	}
+
+	return metadata
+}
+
+//revive:disable:cognitive-complexity
+
+func toMetadataName(name string) string {
+	converted := make([]rune, Empty, len(name))
+
+	previous := '_'
+	for _, r := range name {
+		switch {
+		case r > unicode.MaxASCII:
+			r = '_'
+			converted = append(converted, r)
+		case unicode.IsUpper(r):
+			// Convert camel case to snake case.
+			if previous != '_' && !unicode.IsUpper(previous) {
+				converted = append(converted, '_')
+			}
+
+			converted = append(converted, unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			converted = append(converted, r)
+		default:
+			r = '_'
+			converted = append(converted, r)
+		}
+
+		previous = r
+	}
+
+	return string(converted)
+}
+
+//revive:enable:cognitive-complexity
@@ -0,0 +1,222 @@
+package loki
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+)
+
+const (
+	DefaultBatchMaxBytes   = 1048576
+	DefaultBatchMaxEntries = 1000
+	DefaultBatchMaxWait    = 1
+)
+
+var ErrBatchDiscarded = errors.New("batch is discarded")
+
+// Batcher groups entries into stream by labels, and pushes them in one message
+// before the batch exceeds max bytes or max entries, or after max wait.
+type Batcher struct {
+	maxBytes   int
+	maxEntries int
+	maxWait    time.Duration
+	post       func(ctx context.Context, message *Message) error
+
+	// Locked while pushing to keep order of batches and flushed callbacks.
+	mu      sync.Mutex
+	streams map[string]*Stream
+	bytes   int
+	entries int
+	flushed []func(err error)
+	timer   *time.Timer
+}
+
+func NewBatcher(
+	maxBytes int,
+	maxEntries int,
+	maxWait time.Duration,
+	post func(ctx context.Context, message *Message) error,
+) *Batcher {
+	return &Batcher{
+		maxBytes:   maxBytes,
+		maxEntries: maxEntries,
+		maxWait:    maxWait,
+		post:       post,
+		streams:    map[string]*Stream{},
+	}
+}
+
+// GetBatcher returns batcher shared by events, tasks, alarms and chains of target.
+func GetBatcher(ctx context.Context) *Batcher {
+	maxBytes, ok := ctx.Value(flag.LokiBatchMaxBytesKey{}).(int)
+	if !ok || maxBytes <= Empty {
+		maxBytes = DefaultBatchMaxBytes
+	}
+
+	maxEntries, ok := ctx.Value(flag.LokiBatchMaxEntriesKey{}).(int)
+	if !ok || maxEntries <= Empty {
+		maxEntries = DefaultBatchMaxEntries
+	}
+
+	// Zero is to push every update.
+	maxWait, ok := ctx.Value(flag.LokiBatchMaxWaitKey{}).(int)
+	if !ok || maxWait < Empty {
+		maxWait = DefaultBatchMaxWait
+	}
+
+	return NewBatcher(maxBytes, maxEntries, time.Duration(maxWait)*time.Second, Post)
+}
+
+// Add appends streams to batch, and flushed is called with the result after the batch is pushed.
+func (b *Batcher) Add(ctx context.Context, streams []*Stream, flushed func(err error)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	err := b.appendStreams(ctx, streams)
+
+	if flushed != nil {
+		b.flushed = append(b.flushed, func(e error) {
+			// Entries pushed before the limits are also failed.
+			flushed(errors.Join(err, e))
+		})
+	}
+
+	if b.isFull() || b.maxWait <= time.Duration(Empty) {
+		_ = b.push(ctx)
+		return
+	}
+
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.maxWait, func() {
+			// Push the batch even if caller is cancelled.
+			b.Flush(context.WithoutCancel(ctx))
+		})
+	}
+}
+
+// Flush pushes the batch in one message.
+func (b *Batcher) Flush(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_ = b.push(ctx)
+}
+
+// Discard drops the batch without pushing, and flushed is called with ErrBatchDiscarded.
+func (b *Batcher) Discard() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, flushed := b.take()
+	for _, fn := range flushed {
+		fn(ErrBatchDiscarded)
+	}
+}
+
+func (b *Batcher) appendStreams(ctx context.Context, streams []*Stream) error {
+	var errs []error
+	for _, stream := range streams {
+		for _, entry := range stream.Entries {
+			errs = append(errs, b.appendEntry(ctx, stream.Labels, entry))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (b *Batcher) appendEntry(ctx context.Context, labels string, entry *Entry) error {
+	var err error
+
+	size := proto.Size(entry)
+	if _, ok := b.streams[labels]; !ok {
+		size += len(labels)
+	}
+
+	// Push batch before exceeding the limits.
+	if b.entries != Empty && b.bytes+size > b.maxBytes {
+		err = b.push(ctx)
+		size = proto.Size(entry) + len(labels)
+	}
+
+	stream, ok := b.streams[labels]
+	if !ok {
+		stream = &Stream{Labels: labels}
+		b.streams[labels] = stream
+	}
+
+	stream.Entries = append(stream.Entries, entry)
+	b.bytes += size
+	b.entries++
+
+	if b.entries >= b.maxEntries {
+		err = errors.Join(err, b.push(ctx))
+	}
+
+	return err
+}
+
+func (b *Batcher) isFull() bool {
+	return b.bytes >= b.maxBytes || b.entries >= b.maxEntries
+}
+
+func (b *Batcher) push(ctx context.Context) error {
+	message, flushed := b.take()
+
+	var err error
+	if len(message.Streams) != Empty {
+		err = b.post(ctx, message)
+		if err != nil {
+			warn(ctx, "Failed to post batch to Loki", err)
+		}
+	}
+
+	for _, fn := range flushed {
+		fn(err)
+	}
+
+	return err
+}
+
+// take returns the batch and resets it.
+func (b *Batcher) take() (*Message, []func(err error)) {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	message := b.toMessage()
+	flushed := b.flushed
+
+	b.streams = map[string]*Stream{}
+	b.bytes = Empty
+	b.entries = Empty
+	b.flushed = nil
+
+	return message, flushed
+}
+
+func (b *Batcher) toMessage() *Message {
+	streams := make([]*Stream, Empty, len(b.streams))
+	for _, stream := range b.streams {
+		// Loki may reject out of order entries in stream.
+		slices.SortStableFunc(stream.Entries, func(a *Entry, b *Entry) int {
+			return a.Timestamp.AsTime().Compare(b.Timestamp.AsTime())
+		})
+
+		streams = append(streams, stream)
+	}
+
+	// Sort to push in same order for same batch.
+	slices.SortFunc(streams, func(a *Stream, b *Stream) int {
+		return strings.Compare(a.Labels, b.Labels)
+	})
+
+	return &Message{Streams: streams}
+}
@@ -0,0 +1,222 @@
+package loki
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+//revive:disable:add-constant
+
+type testPoster struct {
+	mu       sync.Mutex
+	messages []*Message
+	err      error
+}
+
+func (p *testPoster) Post(_ context.Context, message *Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.messages = append(p.messages, message)
+	return p.err
+}
+
+func (p *testPoster) Count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.messages)
+}
+
+func testStream(labels string, seconds int64, line string) *Stream {
+	return &Stream{
+		Labels: labels,
+		Entries: []*Entry{
+			{
+				Timestamp: timestamppb.New(time.Unix(seconds, 0)),
+				Line:      line,
+			},
+		},
+	}
+}
+
+func testLines(stream *Stream) string {
+	lines := ""
+	for _, entry := range stream.Entries {
+		lines += entry.Line
+	}
+
+	return lines
+}
+
+func TestBatcher_Flush_Grouped(t *testing.T) {
+	poster := testPoster{}
+	b := NewBatcher(DefaultBatchMaxBytes, DefaultBatchMaxEntries, time.Hour, poster.Post)
+
+	flushed := 0
+	b.Add(t.Context(), []*Stream{
+		testStream(`{kind="event"}`, 3, "c"),
+		testStream(`{kind="task"}`, 2, "x"),
+	}, func(error) { flushed++ })
+	b.Add(t.Context(), []*Stream{
+		testStream(`{kind="event"}`, 1, "a"),
+		testStream(`{kind="event"}`, 2, "b"),
+	}, func(error) { flushed++ })
+
+	if poster.Count() != 0 || flushed != 0 {
+		t.Fatalf("Flushed before max wait: %d", poster.Count())
+	}
+
+	b.Flush(t.Context())
+
+	if poster.Count() != 1 || flushed != 2 {
+		t.Fatalf("Invalid flush: %d %d", poster.Count(), flushed)
+	}
+
+	streams := poster.messages[0].Streams
+	if len(streams) != 2 || streams[0].Labels != `{kind="event"}` {
+		t.Fatalf("Invalid streams: %v", streams)
+	}
+
+	if lines := testLines(streams[0]); lines != "abc" {
+		t.Errorf("Invalid order: %s", lines)
+	}
+}
+
+func TestBatcher_Add_MaxEntries(t *testing.T) {
+	poster := testPoster{}
+	b := NewBatcher(DefaultBatchMaxBytes, 2, time.Hour, poster.Post)
+
+	flushed := false
+	b.Add(t.Context(), []*Stream{
+		testStream(`{kind="event"}`, 1, "a"),
+		testStream(`{kind="event"}`, 2, "b"),
+		testStream(`{kind="event"}`, 3, "c"),
+	}, func(error) { flushed = true })
+
+	if poster.Count() != 1 || len(poster.messages[0].Streams[0].Entries) != 2 {
+		t.Fatalf("Invalid flush: %d", poster.Count())
+	}
+
+	if flushed {
+		t.Error("Flushed before all entries are pushed")
+	}
+
+	b.Flush(t.Context())
+
+	if poster.Count() != 2 || !flushed {
+		t.Errorf("Invalid flush: %d", poster.Count())
+	}
+}
+
+func TestBatcher_Add_MaxBytes(t *testing.T) {
+	poster := testPoster{}
+	b := NewBatcher(40, DefaultBatchMaxEntries, time.Hour, poster.Post)
+
+	b.Add(t.Context(), []*Stream{
+		testStream(`{kind="event"}`, 1, "aaaaaaaaaa"),
+		testStream(`{kind="event"}`, 2, "bbbbbbbbbb"),
+	}, nil)
+
+	if poster.Count() != 1 || len(poster.messages[0].Streams[0].Entries) != 1 {
+		t.Errorf("Invalid flush: %d", poster.Count())
+	}
+}
+
+func TestBatcher_Add_MaxWait(t *testing.T) {
+	poster := testPoster{}
+	b := NewBatcher(DefaultBatchMaxBytes, DefaultBatchMaxEntries, 10*time.Millisecond, poster.Post)
+
+	b.Add(t.Context(), []*Stream{testStream(`{kind="event"}`, 1, "a")}, nil)
+
+	for range 100 {
+		if poster.Count() != 0 {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Error("Not flushed after max wait")
+}
+
+func TestBatcher_Add_MaxWaitCancelled(t *testing.T) {
+	post := func(ctx context.Context, _ *Message) error {
+		return ctx.Err()
+	}
+
+	b := NewBatcher(DefaultBatchMaxBytes, DefaultBatchMaxEntries, 10*time.Millisecond, post)
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	flushed := make(chan error, 1)
+	b.Add(ctx, []*Stream{testStream(`{kind="event"}`, 1, "a")}, func(err error) {
+		flushed <- err
+	})
+
+	// Caller is stopped before max wait.
+	cancel()
+
+	err := <-flushed
+	if err != nil {
+		t.Errorf("Flushed with cancelled context: %v", err)
+	}
+}
+
+func TestBatcher_Flush_Error(t *testing.T) {
+	poster := testPoster{err: errors.New("unavailable")}
+	b := NewBatcher(DefaultBatchMaxBytes, DefaultBatchMaxEntries, time.Hour, poster.Post)
+
+	var flushed error
+	b.Add(t.Context(), []*Stream{testStream(`{kind="event"}`, 1, "a")}, func(err error) {
+		flushed = err
+	})
+	b.Flush(t.Context())
+
+	if poster.Count() != 1 || flushed == nil {
+		t.Errorf("Flushed callback not called with error: %d", poster.Count())
+	}
+}
+
+func TestBatcher_Discard(t *testing.T) {
+	poster := testPoster{}
+	b := NewBatcher(DefaultBatchMaxBytes, DefaultBatchMaxEntries, time.Hour, poster.Post)
+
+	var flushed error
+	b.Add(t.Context(), []*Stream{testStream(`{kind="event"}`, 1, "a")}, func(err error) {
+		flushed = err
+	})
+	b.Discard()
+	b.Flush(t.Context())
+
+	if poster.Count() != 0 || !errors.Is(flushed, ErrBatchDiscarded) {
+		t.Errorf("Invalid discard: %d %v", poster.Count(), flushed)
+	}
+}
+
+func TestBatcher_Add_MaxEntriesError(t *testing.T) {
+	poster := testPoster{err: errors.New("unavailable")}
+	b := NewBatcher(DefaultBatchMaxBytes, 2, time.Hour, poster.Post)
+
+	var flushed error
+	b.Add(t.Context(), []*Stream{
+		testStream(`{kind="event"}`, 1, "a"),
+		testStream(`{kind="event"}`, 2, "b"),
+		testStream(`{kind="event"}`, 3, "c"),
+	}, func(err error) { flushed = err })
+
+	// Push the tail successfully.
+	poster.err = nil
+	b.Flush(t.Context())
+
+	if poster.Count() != 2 || flushed == nil {
+		t.Errorf("Flushed callback not called with error: %d", poster.Count())
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,156 @@
+package loki
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+const DefaultChainIdleTimeout = 60
+
+func CollectChains(
+	ctx context.Context,
+	correlator *vmomi.ChainCorrelator,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+	batcher *Batcher,
+) {
+	queue := retryQueue[vmomi.Chain]{}
+
+	for {
+		// Check completed chains every second.
+		time.Sleep(time.Second)
+
+		chains := queue.take(correlator.Close(time.Now()))
+		if len(chains) == Empty {
+			continue
+		}
+
+		message := ChainsToMessage(&chains, serviceName, vcenter, cfg)
+		batcher.Add(ctx, message.Streams, queue.requeue(chains))
+	}
+}
+
+func NewChainCorrelator(ctx context.Context, cfg *config.Config) *vmomi.ChainCorrelator {
+	idleTimeout, ok := ctx.Value(flag.LokiChainIdleTimeoutKey{}).(int)
+	if !ok || idleTimeout <= Empty {
+		idleTimeout = DefaultChainIdleTimeout
+	}
+
+	return vmomi.NewChainCorrelator(time.Duration(idleTimeout)*time.Second, cfg.GetSeverity)
+}
+
+func ChainsToMessage(
+	chains *[]vmomi.Chain,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+) *Message {
+	streams := make([]*Stream, len(*chains))
+	for i, chain := range *chains {
+		streams[i] = ChainToStream(&chain, serviceName, vcenter, cfg)
+	}
+
+	return &Message{
+		Streams: streams,
+	}
+}
+
+func ChainToStream(
+	chain *vmomi.Chain,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+) *Stream {
+	severity := cfg.GetSeverity(chain.LastEventTypeID, chain.Severity)
+
+	return &Stream{
+		Labels: fmt.Sprintf(
+			`{service_name=%q, severity=%q, vcenter=%q, kind="chain"%s}`,
+			serviceName,
+			severity,
+			vcenter,
+			CreateLevelLabel(severity, &cfg.SeverityConfig),
+		),
+		Entries: []*Entry{
+			{
+				Timestamp:          timestamppb.New(chain.LastTime),
+				Line:               getChainLine(chain),
+				StructuredMetadata: CreateChainMetadata(chain),
+			},
+		},
+	}
+}
+
+func CreateChainMetadata(chain *vmomi.Chain) []*Metadata {
+	//revive:disable:add-constant
+	metadata := make([]*Metadata, 0, 10)
+	//revive:enable:add-constant
+
+	metadata = append(metadata, &Metadata{
+		Name:  "chain_id",
+		Value: fmt.Sprint(chain.ChainID),
+	})
+
+	metadata = appendOptionalMetadata(metadata, "task_key", chain.TaskKey)
+
+	metadata = append(metadata, &Metadata{
+		Name:  "target",
+		Value: chain.Target,
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "first_event_type_id",
+		Value: chain.FirstEventTypeID,
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "last_event_type_id",
+		Value: chain.LastEventTypeID,
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "event_count",
+		Value: fmt.Sprint(chain.EventCount),
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "start_time",
+		Value: chain.FirstTime.Format(time.RFC3339Nano),
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "complete_time",
+		Value: chain.LastTime.Format(time.RFC3339Nano),
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "duration",
+		Value: fmt.Sprint(chain.Duration().Seconds()),
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "outcome",
+		Value: chain.Outcome,
+	})
+
+	return metadata
+}
+
+func getChainLine(chain *vmomi.Chain) string {
+	return fmt.Sprintf(
+		"%s -> %s %s: %s (%d events)",
+		chain.FirstEventTypeID,
+		chain.LastEventTypeID,
+		chain.Target,
+		chain.Outcome,
+		chain.EventCount,
+	)
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/9506hqwy/vmomi-event-source/pkg/checkpoint"
 	"github.com/9506hqwy/vmomi-event-source/pkg/config"
 	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
 	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
@@ -16,6 +18,8 @@ import (
 const Empty = int(0)
 
 func Collect(ctx context.Context) {
+	ctx = WithClient(ctx)
+
 	cfg, err := config.GetConfig(ctx)
 	if err != nil {
 		warn(ctx, "Failed to get config", err)
@@ -27,62 +31,248 @@ func Collect(ctx context.Context) {
 		serviceName = "vmomi-event-source"
 	}
 
-	latestKey := int32(Empty)
+	store := checkpoint.GetStore(ctx)
+
+	if len(cfg.Targets) == Empty {
+		CollectTarget(ctx, serviceName, cfg, store)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, target := range cfg.Targets {
+		tctx := target.WithContext(ctx)
+		wg.Go(func() {
+			CollectTarget(tctx, serviceName, cfg, store)
+		})
+	}
+
+	wg.Wait()
+}
+
+func CollectTarget(
+	ctx context.Context,
+	serviceName string,
+	cfg *config.Config,
+	store *checkpoint.Store,
+) {
+	target := getTargetName(ctx)
+	ctx = context.WithValue(ctx, flag.TargetNameKey{}, target)
+	// Filter of target is prior to global filter.
+	ctx = cfg.WithFilterContext(ctx)
+
+	latest, err := store.Load(target)
+	if err != nil {
+		warn(ctx, "Failed to load checkpoint", err)
+	}
+
+	batcher := GetBatcher(ctx)
+
+	enrichers := startCollectors(ctx, serviceName, target, cfg, batcher)
 
 	for {
 		ch := make(chan *[]vmomi.Event)
 
-		go func() {
-			Watch(ctx, ch, latestKey)
-		}()
+		wctx, cancel := context.WithCancel(ctx)
+
+		go Watch(wctx, ch, latest)
 
-		latestKey = Notify(ctx, ch, serviceName, latestKey, cfg)
+		d := newDelivery(store, target, latest)
+		latest = Notify(ctx, ch, serviceName, cfg, enrichers, batcher, d)
+
+		// Stop watching if pushing fails, and wait until the channel is closed.
+		cancel()
+		for events := range ch {
+			slog.DebugContext(ctx, "Discarded events to collect again", "count", len(*events))
+		}
 
 		// Retry after 3 seconds
 		time.Sleep(time.Duration(3) * time.Second)
 	}
 }
 
-func Watch(ctx context.Context, ch chan<- *[]vmomi.Event, previousKey int32) {
-	err := vmomi.Poll(ctx, nil, ch, previousKey)
+func startCollectors(
+	ctx context.Context,
+	serviceName string,
+	target string,
+	cfg *config.Config,
+	batcher *Batcher,
+) []vmomi.Enricher {
+	correlator := startChainCorrelator(ctx, serviceName, target, cfg, batcher)
+
+	if isCollectTasks(ctx) || correlator != nil {
+		// Tasks are watched to close chain of task even if tasks are not pushed.
+		go CollectTasks(ctx, serviceName, target, cfg, correlator, batcher)
+	}
+
+	collectAlarms, ok := ctx.Value(flag.LokiCollectAlarmsKey{}).(bool)
+	if ok && collectAlarms {
+		go CollectAlarms(ctx, serviceName, target, cfg, batcher)
+	}
+
+	enrichers := startEnrichers(ctx, cfg)
+
+	// Correlator is last to record enriched events.
+	if correlator != nil {
+		enrichers = append(enrichers, correlator)
+	}
+
+	return enrichers
+}
+
+func startChainCorrelator(
+	ctx context.Context,
+	serviceName string,
+	target string,
+	cfg *config.Config,
+	batcher *Batcher,
+) *vmomi.ChainCorrelator {
+	correlateChains, ok := ctx.Value(flag.LokiCorrelateChainsKey{}).(bool)
+	if !ok || !correlateChains {
+		return nil
+	}
+
+	correlator := NewChainCorrelator(ctx, cfg)
+	go CollectChains(ctx, correlator, serviceName, target, cfg, batcher)
+	return correlator
+}
+
+func startEnrichers(ctx context.Context, cfg *config.Config) []vmomi.Enricher {
+	enrichers := []vmomi.Enricher{}
+
+	enrichInventory, ok := ctx.Value(flag.LokiEnrichInventoryKey{}).(bool)
+	if ok && enrichInventory {
+		inventory := vmomi.NewInventory()
+		go WatchInventory(ctx, inventory)
+		enrichers = append(enrichers, inventory)
+	}
+
+	if cfg.Tags != nil {
+		tags := vmomi.NewTagCache(cfg.Tags.Categories)
+		go RefreshTags(ctx, tags, cfg.Tags.GetRefreshSeconds())
+		enrichers = append(enrichers, tags)
+	}
+
+	if len(cfg.CustomFields) != Empty {
+		customFields := vmomi.NewCustomFieldCache(cfg.CustomFields)
+		go WatchCustomFields(ctx, customFields)
+		enrichers = append(enrichers, customFields)
+	}
+
+	locales, ok := ctx.Value(flag.LokiMessageLocalesKey{}).([]string)
+	if ok && len(locales) != Empty {
+		enrichers = append(enrichers, vmomi.NewMessageLocalizer(ctx, locales))
+	}
+
+	return enrichers
+}
+
+func Watch(ctx context.Context, ch chan<- *[]vmomi.Event, previous *vmomi.Checkpoint) {
+	err := vmomi.Poll(ctx, nil, ch, previous)
 	if err != nil {
 		warn(ctx, "Failed to poll events", err)
 	}
 }
 
+//revive:disable:cognitive-complexity
+
 func Notify(
 	ctx context.Context,
 	ch <-chan *[]vmomi.Event,
 	serviceName string,
-	previousKey int32,
 	cfg *config.Config,
-) int32 {
-	latestKey := previousKey
+	enrichers []vmomi.Enricher,
+	batcher *Batcher,
+	d *delivery,
+) *vmomi.Checkpoint {
+	for {
+		select {
+		case events, ok := <-ch:
+			if !ok {
+				// Push the tail of the batch even if cancelled.
+				batcher.Flush(context.WithoutCancel(ctx))
+				return d.latest
+			}
+
+			if len(*events) == Empty {
+				continue
+			}
+
+			for _, enricher := range enrichers {
+				enricher.Enrich(events)
+			}
+
+			streams := ToStreams(events, serviceName, d.target, cfg)
+			batcher.Add(ctx, streams, d.flushed(ctx, getLastEventCheckpoint(events)))
+		case <-d.failed:
+			// Drop the events after the failed batch not to push them twice.
+			batcher.Discard()
+
+			// Resume from the last delivered event.
+			return d.latest
+		}
+	}
+}
 
-	for events := range ch {
-		latestKey = getLastEventKey(events)
+//revive:enable:cognitive-complexity
 
-		message := ToMessage(events, serviceName, cfg)
-		if len(message.Streams) == Empty {
-			continue
+// delivery saves checkpoint after the events are pushed,
+// and stops saving after pushing fails not to skip undelivered events.
+type delivery struct {
+	store  *checkpoint.Store
+	target string
+	latest *vmomi.Checkpoint
+	failed chan struct{}
+}
+
+func newDelivery(store *checkpoint.Store, target string, latest *vmomi.Checkpoint) *delivery {
+	return &delivery{
+		store:  store,
+		target: target,
+		latest: latest,
+		failed: make(chan struct{}),
+	}
+}
+
+// flushed is called in order of pushed batches.
+func (d *delivery) flushed(ctx context.Context, current *vmomi.Checkpoint) func(err error) {
+	return func(err error) {
+		select {
+		case <-d.failed:
+			return
+		default:
 		}
 
-		err := Post(ctx, message)
 		if err != nil {
-			warn(ctx, "Failed to post event to Loki", err)
+			close(d.failed)
+			return
 		}
-	}
 
-	return latestKey
+		d.latest = current
+
+		err = d.store.Save(d.target, d.latest)
+		if err != nil {
+			warn(ctx, "Failed to save checkpoint", err)
+		}
+	}
 }
 
-func ToMessage(events *[]vmomi.Event, serviceName string, cfg *config.Config) *Message {
+func ToMessage(
+	events *[]vmomi.Event,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+) *Message {
 	return &Message{
-		Streams: ToStreams(events, serviceName, cfg),
+		Streams: ToStreams(events, serviceName, vcenter, cfg),
 	}
 }
 
-func ToStreams(events *[]vmomi.Event, serviceName string, cfg *config.Config) []*Stream {
+func ToStreams(
+	events *[]vmomi.Event,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+) []*Stream {
 	streams := make([]*Stream, Empty, len(*events))
 
 	for _, event := range *events {
@@ -90,21 +280,41 @@ func ToStreams(events *[]vmomi.Event, serviceName string, cfg *config.Config) []
 			continue
 		}
 
-		streams = append(streams, ToStream(&event, serviceName))
+		streams = append(streams, ToStream(&event, serviceName, vcenter, cfg))
 	}
 
 	return streams
 }
 
-func ToStream(event *vmomi.Event, serviceName string) *Stream {
+func ToStream(
+	event *vmomi.Event,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+) *Stream {
+	attributes := cfg.SelectAttributes(event.EventTypeID, event.GetAttributes)
+
 	metadata := CreateMetadata(event)
+	metadata = append(metadata, CreateAttributeMetadata(attributes)...)
+	metadata = append(metadata, CreateTagMetadata(event.Tags, cfg.Tags)...)
+	metadata = append(metadata, CreateCustomFieldMetadata(event.CustomFields)...)
+	metadata = append(metadata, CreateMessageMetadata(event.Messages)...)
+
+	severity := cfg.GetSeverity(event.EventTypeID, event.Severity)
 
 	return &Stream{
-		Labels: fmt.Sprintf(`{service_name="%s", severity="%s"}`, serviceName, event.Severity),
+		Labels: fmt.Sprintf(
+			`{service_name=%q, severity=%q, vcenter=%q, kind="event"%s%s}`,
+			serviceName,
+			severity,
+			vcenter,
+			CreateLevelLabel(severity, &cfg.SeverityConfig),
+			CreateTagLabels(event.Tags, cfg.Tags),
+		),
 		Entries: []*Entry{
 			{
 				Timestamp:          timestamppb.New(event.CreatedTime),
-				Line:               event.FullFormattedMessage,
+				Line:               event.Message,
 				StructuredMetadata: metadata,
 			},
 		},
@@ -121,11 +331,9 @@ func containsExcludes(event *vmomi.Event, cfg *config.Config) bool {
 	return false
 }
 
-//revive:disable:cognitive-complexity
-
 func CreateMetadata(event *vmomi.Event) []*Metadata {
 	//revive:disable:add-constant
-	metadata := make([]*Metadata, 0, 10)
+	metadata := make([]*Metadata, 0, 20)
 	//revive:enable:add-constant
 
 	metadata = append(metadata, &Metadata{
@@ -133,59 +341,51 @@ func CreateMetadata(event *vmomi.Event) []*Metadata {
 		Value: fmt.Sprint(event.Key),
 	})
 
-	if event.ComputeResource != nil && *event.ComputeResource != *event.Host {
-		metadata = append(metadata, &Metadata{
-			Name:  "cluster",
-			Value: *event.ComputeResource,
-		})
-	}
+	metadata = append(metadata, &Metadata{
+		Name:  "chain_id",
+		Value: fmt.Sprint(event.ChainID),
+	})
 
-	if event.Datacenter != nil {
-		metadata = append(metadata, &Metadata{
-			Name:  "datacenter",
-			Value: *event.Datacenter,
-		})
-	}
+	metadata = appendOptionalMetadata(metadata, "change_tag", event.ChangeTag)
 
-	if event.Datastore != nil {
-		metadata = append(metadata, &Metadata{
-			Name:  "datastore",
-			Value: *event.Datastore,
-		})
-	}
+	metadata = appendOptionalMetadata(metadata, "task_key", event.TaskKey)
 
-	if event.DistributedVirtualSwitch != nil {
-		metadata = append(metadata, &Metadata{
-			Name:  "distributed_virtual_switch",
-			Value: *event.DistributedVirtualSwitch,
-		})
+	if !event.IsStandaloneHost() {
+		metadata = appendEntityMetadata(
+			metadata,
+			"cluster",
+			event.ComputeResource,
+			event.ComputeResourceID,
+		)
 	}
 
-	if event.Host != nil {
-		metadata = append(metadata, &Metadata{
-			Name:  "host",
-			Value: *event.Host,
-		})
-	}
+	metadata = appendEntityMetadata(metadata, "datacenter", event.Datacenter, event.DatacenterID)
 
-	if event.Network != nil {
-		metadata = append(metadata, &Metadata{
-			Name:  "network",
-			Value: *event.Network,
-		})
-	}
+	metadata = appendEntityMetadata(metadata, "datastore", event.Datastore, event.DatastoreID)
+
+	metadata = appendEntityMetadata(
+		metadata,
+		"distributed_virtual_switch",
+		event.DistributedVirtualSwitch,
+		event.DistributedVirtualSwitchID,
+	)
+
+	metadata = appendEntityMetadata(metadata, "host", event.Host, event.HostID)
+
+	metadata = appendEntityMetadata(metadata, "network", event.Network, event.NetworkID)
 
 	metadata = append(metadata, &Metadata{
 		Name:  "user",
 		Value: event.UserName,
 	})
 
-	if event.VM != nil {
-		metadata = append(metadata, &Metadata{
-			Name:  "vm",
-			Value: *event.VM,
-		})
-	}
+	metadata = appendEntityMetadata(metadata, "vm", event.VM, event.VMID)
+
+	metadata = appendOptionalMetadata(metadata, "inventory_path", event.InventoryPath)
+
+	metadata = appendOptionalMetadata(metadata, "folder", event.Folder)
+
+	metadata = appendOptionalMetadata(metadata, "resource_pool", event.ResourcePool)
 
 	metadata = append(metadata, &Metadata{
 		Name:  "event_type_id",
@@ -195,15 +395,60 @@ func CreateMetadata(event *vmomi.Event) []*Metadata {
 	return metadata
 }
 
-//revive:enable:cognitive-complexity
+func appendEntityMetadata(
+	metadata []*Metadata,
+	name string,
+	entity *string,
+	id *string,
+) []*Metadata {
+	if entity == nil {
+		return metadata
+	}
+
+	metadata = append(metadata, &Metadata{
+		Name:  name,
+		Value: *entity,
+	})
+
+	return appendOptionalMetadata(metadata, name+"_id", id)
+}
+
+func appendOptionalMetadata(metadata []*Metadata, name string, value *string) []*Metadata {
+	if value == nil {
+		return metadata
+	}
+
+	return append(metadata, &Metadata{
+		Name:  name,
+		Value: *value,
+	})
+}
 
-func getLastEventKey(events *[]vmomi.Event) int32 {
+func getLastEventCheckpoint(events *[]vmomi.Event) *vmomi.Checkpoint {
 	//revive:disable:add-constant
 	lastEvent := (*events)[len(*events)-1]
 	//revive:enable:add-constant
-	return lastEvent.Key
+	return &vmomi.Checkpoint{
+		Key:         lastEvent.Key,
+		CreatedTime: lastEvent.CreatedTime,
+	}
+}
+
+func getTargetName(ctx context.Context) string {
+	target, err := vmomi.GetTarget(ctx)
+	if err != nil {
+		return ""
+	}
+
+	return target.Name
 }
 
 func warn(ctx context.Context, msg string, err error) {
-	slog.WarnContext(ctx, msg, "error", err)
+	target, ok := ctx.Value(flag.TargetNameKey{}).(string)
+	if !ok {
+		slog.WarnContext(ctx, msg, "error", err)
+		return
+	}
+
+	slog.WarnContext(ctx, msg, "error", err, "vcenter", target)
 }
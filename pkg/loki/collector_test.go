@@ -0,0 +1,132 @@
+package loki
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/checkpoint"
+	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+//revive:disable:add-constant
+
+func testLokiContext(t *testing.T, handler http.HandlerFunc) context.Context {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	ctx := context.WithValue(t.Context(), flag.LokiURLKey{}, server.URL)
+	ctx = context.WithValue(ctx, flag.LokiNoVerifySSLKey{}, false)
+	// Push every update.
+	return context.WithValue(ctx, flag.LokiBatchMaxWaitKey{}, 0)
+}
+
+func testEvents(key int32) *[]vmomi.Event {
+	return &[]vmomi.Event{
+		{
+			Key:         key,
+			CreatedTime: time.Unix(int64(key), 0),
+			EventTypeID: "UserLoginSessionEvent",
+			Message:     "event",
+		},
+	}
+}
+
+func TestNotify_PostError(t *testing.T) {
+	// Fail to push the second events.
+	var count atomic.Int32
+	ctx := testLokiContext(t, func(w http.ResponseWriter, _ *http.Request) {
+		if count.Add(1) == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	store := checkpoint.NewStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+
+	ch := make(chan *[]vmomi.Event, 3)
+	ch <- testEvents(1)
+	ch <- testEvents(2)
+	ch <- testEvents(3)
+
+	d := newDelivery(store, "vc", nil)
+	latest := Notify(ctx, ch, "test", config.DefaultConfig(), nil, GetBatcher(ctx), d)
+	if latest == nil || latest.Key != 1 {
+		t.Fatalf("Invalid latest checkpoint: %v", latest)
+	}
+
+	saved, err := store.Load("vc")
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	if saved == nil || saved.Key != 1 {
+		t.Errorf("Invalid saved checkpoint: %v", saved)
+	}
+}
+
+func TestNotify_Closed(t *testing.T) {
+	ctx := testLokiContext(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	ch := make(chan *[]vmomi.Event, 2)
+	ch <- testEvents(1)
+	ch <- testEvents(2)
+	close(ch)
+
+	d := newDelivery(nil, "vc", nil)
+	latest := Notify(ctx, ch, "test", config.DefaultConfig(), nil, GetBatcher(ctx), d)
+	if latest == nil || latest.Key != 2 {
+		t.Errorf("Invalid latest checkpoint: %v", latest)
+	}
+}
+
+func TestNotify_FailedDiscard(t *testing.T) {
+	poster := testPoster{}
+	b := NewBatcher(DefaultBatchMaxBytes, DefaultBatchMaxEntries, time.Hour, poster.Post)
+
+	// Events added after the failed batch are left in batch.
+	streams := ToStreams(testEvents(2), "test", "vc", config.DefaultConfig())
+
+	var flushed error
+	b.Add(t.Context(), streams, func(err error) {
+		flushed = err
+	})
+
+	d := newDelivery(nil, "vc", nil)
+	close(d.failed)
+
+	ch := make(chan *[]vmomi.Event)
+	Notify(t.Context(), ch, "test", config.DefaultConfig(), nil, b, d)
+
+	b.Flush(t.Context())
+
+	if poster.Count() != 0 || !errors.Is(flushed, ErrBatchDiscarded) {
+		t.Errorf("Pushed events to collect again: %d %v", poster.Count(), flushed)
+	}
+}
+
+func TestToStream_EscapeLabel(t *testing.T) {
+	events := testEvents(1)
+
+	stream := ToStream(&(*events)[0], "test", `vc"01`, config.DefaultConfig())
+
+	if !strings.Contains(stream.Labels, `vcenter="vc\"01"`) {
+		t.Errorf("Invalid labels: %s", stream.Labels)
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,42 @@
+package loki
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+const CustomFieldPrefix = "custom_"
+
+func WatchCustomFields(ctx context.Context, customFields *vmomi.CustomFieldCache) {
+	for {
+		err := customFields.Watch(ctx, nil)
+		if err != nil {
+			warn(ctx, "Failed to watch custom fields", err)
+		}
+
+		// Retry after 3 seconds
+		time.Sleep(time.Duration(3) * time.Second)
+	}
+}
+
+func CreateCustomFieldMetadata(customFields map[string]string) []*Metadata {
+	names := make([]string, Empty, len(customFields))
+	for name := range customFields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	metadata := make([]*Metadata, Empty, len(names))
+	for _, name := range names {
+		metadata = append(metadata, &Metadata{
+			Name:  CustomFieldPrefix + toMetadataName(name),
+			Value: customFields[name],
+		})
+	}
+
+	return metadata
+}
@@ -0,0 +1,20 @@
+package loki
+
+import (
+	"context"
+	"time"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+func WatchInventory(ctx context.Context, inventory *vmomi.Inventory) {
+	for {
+		err := inventory.Watch(ctx, nil)
+		if err != nil {
+			warn(ctx, "Failed to watch inventory", err)
+		}
+
+		// Retry after 3 seconds
+		time.Sleep(time.Duration(3) * time.Second)
+	}
+}
@@ -0,0 +1,24 @@
+package loki
+
+import "sort"
+
+const MessagePrefix = "message_"
+
+func CreateMessageMetadata(messages map[string]string) []*Metadata {
+	locales := make([]string, Empty, len(messages))
+	for locale := range messages {
+		locales = append(locales, locale)
+	}
+
+	sort.Strings(locales)
+
+	metadata := make([]*Metadata, Empty, len(locales))
+	for _, locale := range locales {
+		metadata = append(metadata, &Metadata{
+			Name:  MessagePrefix + toMetadataName(locale),
+			Value: messages[locale],
+		})
+	}
+
+	return metadata
+}
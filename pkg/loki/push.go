@@ -8,11 +8,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 
 	"github.com/klauspost/compress/snappy"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/9506hqwy/vmomi-event-source/pkg/credential"
 	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+	"github.com/9506hqwy/vmomi-event-source/pkg/transport"
 )
 
 const (
@@ -20,15 +24,74 @@ const (
 	XScopeOrgID         = "X-Scope-OrgID"
 )
 
+// Refresh credential periodically even if pushing succeeds.
+const CredentialTTL = 5 * time.Minute
+
+type clientKey struct{}
+
+// Client keeps the resolved credential and the transport between pushes
+// not to run the credential helper and read the certificates on every push.
+type Client struct {
+	mu        sync.Mutex
+	cred      *credential.Credential
+	resolved  time.Time
+	transport *http.Transport
+}
+
+func NewClient() *Client {
+	return &Client{}
+}
+
+// WithClient shares client between pushes in context.
+func WithClient(ctx context.Context) context.Context {
+	return context.WithValue(ctx, clientKey{}, NewClient())
+}
+
+func getClient(ctx context.Context) *Client {
+	client, ok := ctx.Value(clientKey{}).(*Client)
+	if !ok {
+		return NewClient()
+	}
+
+	return client
+}
+
 func Post(ctx context.Context, message *Message) error {
+	client := getClient(ctx)
+
+	status, err := client.post(ctx, message)
+	if err == nil && isUnauthorized(*status) {
+		// Push again with credential rotated.
+		client.invalidate()
+		status, err = client.post(ctx, message)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	//revive:disable:add-constant
+	if (*status / 100) != 2 {
+		return fmt.Errorf(
+			"failed to post message to loki: status code %d message=%v",
+			*status,
+			message,
+		)
+	}
+	//revive:enable:add-constant
+
+	return nil
+}
+
+func (c *Client) post(ctx context.Context, message *Message) (*int, error) {
 	lokiURL, ok := ctx.Value(flag.LokiURLKey{}).(string)
 	if !ok {
-		return errors.New("url not found in context")
+		return nil, errors.New("url not found in context")
 	}
 
-	noVerifySSL, ok := ctx.Value(flag.LokiNoVerifySSLKey{}).(bool)
-	if !ok {
-		return errors.New("loki_no_verify_ssl not found in context")
+	t, err := c.getTransport(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	tenantID, ok := ctx.Value(flag.LokiTenantIDKey{}).(string)
@@ -38,31 +101,72 @@ func Post(ctx context.Context, message *Message) error {
 
 	endpoint, err := url.Parse(lokiURL)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	req, err := createRequest(ctx, endpoint, message, tenantID)
+	cred, err := c.getCredential(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	tlsConfig := tls.Config{InsecureSkipVerify: noVerifySSL}
-	status, err := send(req, &tlsConfig)
+	req, err := createRequest(ctx, endpoint, message, tenantID, cred)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	//revive:disable:add-constant
-	if (*status / 100) != 2 {
-		return fmt.Errorf(
-			"failed to post message to loki: status code %d message=%v",
-			*status,
-			message,
-		)
+	return send(req, t)
+}
+
+func (c *Client) getCredential(ctx context.Context) (*credential.Credential, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cred != nil && time.Since(c.resolved) < CredentialTTL {
+		return c.cred, nil
 	}
-	//revive:enable:add-constant
 
-	return nil
+	cred, err := credential.Resolve(
+		ctx,
+		flag.OptionalValue(ctx, flag.LokiCredentialKey{}),
+		flag.OptionalValue(ctx, flag.CredentialKeyFileKey{}),
+		flag.OptionalValue(ctx, flag.LokiUserKey{}),
+		flag.OptionalValue(ctx, flag.LokiPasswordKey{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cred = cred
+	c.resolved = time.Now()
+	return cred, nil
+}
+
+func (c *Client) getTransport(ctx context.Context) (*http.Transport, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.transport != nil {
+		return c.transport, nil
+	}
+
+	t, err := newTransport(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.transport = t
+	return t, nil
+}
+
+func (c *Client) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cred = nil
+}
+
+func isUnauthorized(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden
 }
 
 func createRequest(
@@ -70,6 +174,7 @@ func createRequest(
 	endpoint *url.URL,
 	message *Message,
 	tenantID string,
+	cred *credential.Credential,
 ) (*http.Request, error) {
 	buf, err := encode(message)
 	if err != nil {
@@ -83,6 +188,10 @@ func createRequest(
 
 	req.Header.Set("Content-Type", ContentTypeProtobuf)
 
+	if len(cred.Username) != Empty || len(cred.Password) != Empty {
+		req.SetBasicAuth(cred.Username, cred.Password)
+	}
+
 	if tenantID != "" {
 		req.Header.Set(XScopeOrgID, tenantID)
 	}
@@ -90,6 +199,45 @@ func createRequest(
 	return req, nil
 }
 
+func newTransport(ctx context.Context) (*http.Transport, error) {
+	tlsConfig, err := getTLSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyOptions := transport.ProxyOptions{
+		URL:     flag.OptionalValue(ctx, flag.LokiProxyKey{}),
+		NoProxy: flag.OptionalValues(ctx, flag.LokiNoProxyKey{}),
+	}
+
+	proxy, err := proxyOptions.ProxyFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Transport{
+		Proxy:           proxy,
+		TLSClientConfig: tlsConfig,
+	}, nil
+}
+
+func getTLSConfig(ctx context.Context) (*tls.Config, error) {
+	noVerifySSL, ok := ctx.Value(flag.LokiNoVerifySSLKey{}).(bool)
+	if !ok {
+		return nil, errors.New("loki_no_verify_ssl not found in context")
+	}
+
+	tlsOptions := transport.TLSOptions{
+		NoVerifySSL: noVerifySSL,
+		CABundle:    flag.OptionalValues(ctx, flag.LokiCABundleKey{}),
+		CertFile:    flag.OptionalValue(ctx, flag.LokiCertFileKey{}),
+		KeyFile:     flag.OptionalValue(ctx, flag.LokiKeyFileKey{}),
+		MinVersion:  flag.OptionalValue(ctx, flag.LokiTLSMinVersionKey{}),
+	}
+
+	return tlsOptions.Config()
+}
+
 func encode(message *Message) ([]byte, error) {
 	buf, err := proto.Marshal(message)
 	if err != nil {
@@ -100,12 +248,10 @@ func encode(message *Message) ([]byte, error) {
 	return buf, nil
 }
 
-func send(req *http.Request, tlsConfig *tls.Config) (*int, error) {
+func send(req *http.Request, t *http.Transport) (*int, error) {
 	client := http.Client{}
 
-	client.Transport = &http.Transport{
-		TLSClientConfig: tlsConfig,
-	}
+	client.Transport = t
 
 	res, err := client.Do(req)
 	if err != nil {
@@ -0,0 +1,123 @@
+package loki
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+)
+
+//revive:disable:add-constant
+
+func testCredentialContext(t *testing.T, handler http.HandlerFunc) (context.Context, string) {
+	t.Helper()
+
+	// Count credential helper runs.
+	count := filepath.Join(t.TempDir(), "count")
+	helper := filepath.Join(t.TempDir(), "helper.sh")
+	script := "#!/bin/sh\necho >> " + count + "\necho '{\"password\": \"pass\"}'\n"
+
+	err := os.WriteFile(helper, []byte(script), 0o700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithClient(testLokiContext(t, handler))
+	ctx = context.WithValue(ctx, flag.LokiCredentialKey{}, "exec:"+helper)
+	return ctx, count
+}
+
+func testHelperCount(t *testing.T, count string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(count)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.Count(string(data), "\n")
+}
+
+func TestPost_CachedCredential(t *testing.T) {
+	ctx, count := testCredentialContext(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	for range 2 {
+		err := Post(ctx, &Message{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if runs := testHelperCount(t, count); runs != 1 {
+		t.Errorf("Invalid helper runs: %d", runs)
+	}
+}
+
+func TestPost_Unauthorized(t *testing.T) {
+	var requests atomic.Int32
+
+	ctx, count := testCredentialContext(t, func(w http.ResponseWriter, _ *http.Request) {
+		// Reject the first request as the credential is rotated.
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	err := Post(ctx, &Message{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if runs := testHelperCount(t, count); runs != 2 || requests.Load() != 2 {
+		t.Errorf("Invalid helper runs: %d requests: %d", runs, requests.Load())
+	}
+}
+
+func TestPost_TransportReused(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	block := pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+
+	err := os.WriteFile(caBundle, pem.EncodeToMemory(&block), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithClient(t.Context())
+	ctx = context.WithValue(ctx, flag.LokiURLKey{}, server.URL)
+	ctx = context.WithValue(ctx, flag.LokiNoVerifySSLKey{}, false)
+	ctx = context.WithValue(ctx, flag.LokiCABundleKey{}, []string{caBundle})
+
+	err = Post(ctx, &Message{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// CA bundle is not read again.
+	err = os.Remove(caBundle)
+	if err == nil {
+		err = Post(ctx, &Message{})
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,33 @@
+package loki
+
+import (
+	"sync"
+)
+
+// retryQueue keeps items failed to push to push again.
+type retryQueue[T any] struct {
+	mu    sync.Mutex
+	items []T
+}
+
+func (q *retryQueue[T]) take(items []T) []T {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	taken := append(q.items, items...)
+	q.items = nil
+	return taken
+}
+
+func (q *retryQueue[T]) requeue(items []T) func(err error) {
+	return func(err error) {
+		if err == nil {
+			return
+		}
+
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		q.items = append(q.items, items...)
+	}
+}
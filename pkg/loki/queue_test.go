@@ -0,0 +1,31 @@
+package loki
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+//revive:disable:add-constant
+
+func TestRetryQueue_Requeue(t *testing.T) {
+	queue := retryQueue[vmomi.Chain]{}
+
+	chains := queue.take([]vmomi.Chain{{ChainID: 1}})
+	queue.requeue(chains)(nil)
+
+	if chains = queue.take(nil); len(chains) != 0 {
+		t.Errorf("Pushed chains requeued: %v", chains)
+	}
+
+	chains = queue.take([]vmomi.Chain{{ChainID: 2}})
+	queue.requeue(chains)(errors.New("unavailable"))
+
+	chains = queue.take([]vmomi.Chain{{ChainID: 3}})
+	if len(chains) != 2 || chains[0].ChainID != 2 || chains[1].ChainID != 3 {
+		t.Errorf("Invalid chains: %v", chains)
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,16 @@
+package loki
+
+import (
+	"fmt"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+)
+
+func CreateLevelLabel(severity string, cfg *config.SeverityConfig) string {
+	level := cfg.GetLevel(severity)
+	if level == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(`, level=%q`, *level)
+}
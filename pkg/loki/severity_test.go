@@ -0,0 +1,65 @@
+package loki
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+//revive:disable:add-constant
+
+func testSeverityConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Severity = &config.Severity{
+		Overrides: []config.SeverityOverride{
+			{TaskTypeID: "VirtualMachine.powerOff", Severity: "critical"},
+			{AlarmName: "Host connection*", Severity: "critical"},
+		},
+		Mappings: map[string]string{"warning": "warn"},
+		Level:    true,
+	}
+
+	return cfg
+}
+
+func TestStreams_Severity(t *testing.T) {
+	cfg := testSeverityConfig()
+
+	task := vmomi.Task{DescriptionID: "VirtualMachine.powerOff", Severity: "info"}
+	alarm := vmomi.Alarm{
+		AlarmID:  "alarm-1",
+		Name:     "Host connection and power state",
+		Severity: "error",
+		Time:     time.Now(),
+	}
+	chain := vmomi.Chain{LastEventTypeID: "VmMigratedEvent", Severity: "warning"}
+
+	cases := map[string]struct {
+		stream   *Stream
+		expected string
+	}{
+		"task": {
+			stream:   TaskToStream(&task, "test", "vc01", cfg),
+			expected: `severity="critical", vcenter="vc01", kind="task", level="critical"}`,
+		},
+		"alarm": {
+			stream:   AlarmToStream(&alarm, "test", "vc01", cfg),
+			expected: `severity="critical", vcenter="vc01", kind="alarm", level="critical"}`,
+		},
+		"chain": {
+			stream:   ChainToStream(&chain, "test", "vc01", cfg),
+			expected: `severity="warn", vcenter="vc01", kind="chain", level="warn"}`,
+		},
+	}
+
+	for name, c := range cases {
+		if !strings.HasSuffix(c.stream.Labels, c.expected) {
+			t.Errorf("%s: Invalid labels: %s", name, c.stream.Labels)
+		}
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,85 @@
+package loki
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+const TagPrefix = "tag_"
+
+const TagValueSeparator = ","
+
+func RefreshTags(ctx context.Context, tags *vmomi.TagCache, refreshSeconds int) {
+	for {
+		err := tags.Refresh(ctx)
+		if err != nil {
+			warn(ctx, "Failed to refresh tags", err)
+
+			// Retry after 3 seconds
+			time.Sleep(time.Duration(3) * time.Second)
+			continue
+		}
+
+		time.Sleep(time.Duration(refreshSeconds) * time.Second)
+	}
+}
+
+func CreateTagMetadata(tags map[string][]string, cfg *config.Tags) []*Metadata {
+	if cfg == nil {
+		return nil
+	}
+
+	metadata := []*Metadata{}
+	for _, category := range getTagCategories(tags) {
+		if slices.Contains(cfg.Labels, category) {
+			continue
+		}
+
+		metadata = append(metadata, &Metadata{
+			Name:  TagPrefix + toMetadataName(category),
+			Value: strings.Join(tags[category], TagValueSeparator),
+		})
+	}
+
+	return metadata
+}
+
+func CreateTagLabels(tags map[string][]string, cfg *config.Tags) string {
+	if cfg == nil {
+		return ""
+	}
+
+	labels := []string{}
+	for _, category := range getTagCategories(tags) {
+		if !slices.Contains(cfg.Labels, category) {
+			continue
+		}
+
+		labels = append(labels, fmt.Sprintf(
+			`, %s%s=%q`,
+			TagPrefix,
+			toMetadataName(category),
+			strings.Join(tags[category], TagValueSeparator),
+		))
+	}
+
+	return strings.Join(labels, "")
+}
+
+func getTagCategories(tags map[string][]string) []string {
+	categories := make([]string, Empty, len(tags))
+	for category := range tags {
+		categories = append(categories, category)
+	}
+
+	sort.Strings(categories)
+
+	return categories
+}
@@ -0,0 +1,227 @@
+package loki
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+func CollectTasks(
+	ctx context.Context,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+	correlator *vmomi.ChainCorrelator,
+	batcher *Batcher,
+) {
+	queue := retryQueue[vmomi.Task]{}
+
+	for {
+		ch := make(chan *[]vmomi.Task)
+
+		go WatchTasks(ctx, ch)
+
+		NotifyTasks(ctx, ch, serviceName, vcenter, cfg, correlator, batcher, &queue)
+
+		// Retry after 3 seconds
+		time.Sleep(time.Duration(3) * time.Second)
+	}
+}
+
+func WatchTasks(ctx context.Context, ch chan<- *[]vmomi.Task) {
+	err := vmomi.PollTasks(ctx, nil, ch)
+	if err != nil {
+		warn(ctx, "Failed to poll tasks", err)
+	}
+}
+
+//revive:disable:cognitive-complexity
+
+func NotifyTasks(
+	ctx context.Context,
+	ch <-chan *[]vmomi.Task,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+	correlator *vmomi.ChainCorrelator,
+	batcher *Batcher,
+	queue *retryQueue[vmomi.Task],
+) {
+	push := isCollectTasks(ctx)
+
+	// Push the tasks failed to push again every second.
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case tasks, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			correlator.CompleteTasks(*tasks, time.Now())
+			if push {
+				pushTasks(ctx, queue.take(*tasks), serviceName, vcenter, cfg, batcher, queue)
+			}
+		case <-ticker.C:
+			pushTasks(ctx, queue.take(nil), serviceName, vcenter, cfg, batcher, queue)
+		}
+	}
+}
+
+//revive:enable:cognitive-complexity
+
+func pushTasks(
+	ctx context.Context,
+	tasks []vmomi.Task,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+	batcher *Batcher,
+	queue *retryQueue[vmomi.Task],
+) {
+	if len(tasks) == Empty {
+		return
+	}
+
+	message := TasksToMessage(&tasks, serviceName, vcenter, cfg)
+	batcher.Add(ctx, message.Streams, queue.requeue(tasks))
+}
+
+func isCollectTasks(ctx context.Context) bool {
+	collectTasks, ok := ctx.Value(flag.LokiCollectTasksKey{}).(bool)
+	return ok && collectTasks
+}
+
+func TasksToMessage(
+	tasks *[]vmomi.Task,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+) *Message {
+	streams := make([]*Stream, len(*tasks))
+	for i, task := range *tasks {
+		streams[i] = TaskToStream(&task, serviceName, vcenter, cfg)
+	}
+
+	return &Message{
+		Streams: streams,
+	}
+}
+
+func TaskToStream(
+	task *vmomi.Task,
+	serviceName string,
+	vcenter string,
+	cfg *config.Config,
+) *Stream {
+	timestamp := task.QueueTime
+	if task.CompleteTime != nil {
+		timestamp = *task.CompleteTime
+	}
+
+	severity := cfg.GetTaskSeverity(task.DescriptionID, task.Severity)
+
+	return &Stream{
+		Labels: fmt.Sprintf(
+			`{service_name=%q, severity=%q, vcenter=%q, kind="task"%s}`,
+			serviceName,
+			severity,
+			vcenter,
+			CreateLevelLabel(severity, &cfg.SeverityConfig),
+		),
+		Entries: []*Entry{
+			{
+				Timestamp:          timestamppb.New(timestamp),
+				Line:               getTaskLine(task),
+				StructuredMetadata: CreateTaskMetadata(task),
+			},
+		},
+	}
+}
+
+func CreateTaskMetadata(task *vmomi.Task) []*Metadata {
+	//revive:disable:add-constant
+	metadata := make([]*Metadata, 0, 10)
+	//revive:enable:add-constant
+
+	metadata = append(metadata, &Metadata{
+		Name:  "task_key",
+		Value: task.Key,
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "task_type_id",
+		Value: task.DescriptionID,
+	})
+
+	metadata = append(metadata, &Metadata{
+		Name:  "state",
+		Value: task.State,
+	})
+
+	if task.Entity != nil {
+		metadata = append(metadata, &Metadata{
+			Name:  "entity",
+			Value: *task.Entity,
+		})
+
+		metadata = append(metadata, &Metadata{
+			Name:  "entity_id",
+			Value: *task.EntityID,
+		})
+	}
+
+	metadata = append(metadata, &Metadata{
+		Name:  "user",
+		Value: task.Initiator,
+	})
+
+	if task.StartTime != nil {
+		metadata = append(metadata, &Metadata{
+			Name:  "start_time",
+			Value: task.StartTime.Format(time.RFC3339Nano),
+		})
+	}
+
+	if task.CompleteTime != nil {
+		metadata = append(metadata, &Metadata{
+			Name:  "complete_time",
+			Value: task.CompleteTime.Format(time.RFC3339Nano),
+		})
+	}
+
+	metadata = append(metadata, &Metadata{
+		Name:  "duration",
+		Value: fmt.Sprint(task.Duration().Seconds()),
+	})
+
+	if task.Error != nil {
+		metadata = append(metadata, &Metadata{
+			Name:  "error",
+			Value: *task.Error,
+		})
+	}
+
+	return metadata
+}
+
+func getTaskLine(task *vmomi.Task) string {
+	line := task.Description
+	if task.Entity != nil {
+		line = fmt.Sprintf("%s %s", line, *task.Entity)
+	}
+
+	if task.Error != nil {
+		return fmt.Sprintf("%s: %s: %s", line, task.State, *task.Error)
+	}
+
+	return fmt.Sprintf("%s: %s", line, task.State)
+}
@@ -0,0 +1,64 @@
+package loki
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+//revive:disable:add-constant
+
+func TestNotifyTasks_Batched(t *testing.T) {
+	ctx := context.WithValue(t.Context(), flag.LokiCollectTasksKey{}, true)
+
+	poster := testPoster{}
+	b := NewBatcher(DefaultBatchMaxBytes, DefaultBatchMaxEntries, time.Hour, poster.Post)
+
+	ch := make(chan *[]vmomi.Task, 2)
+	ch <- &[]vmomi.Task{{Key: "task-1", State: "success", Severity: "info"}}
+	ch <- &[]vmomi.Task{{Key: "task-2", State: "success", Severity: "info"}}
+	close(ch)
+
+	NotifyTasks(ctx, ch, "test", "vc", config.DefaultConfig(), nil, b, &retryQueue[vmomi.Task]{})
+
+	if poster.Count() != 0 {
+		t.Fatalf("Pushed before flush: %d", poster.Count())
+	}
+
+	b.Flush(ctx)
+
+	if poster.Count() != 1 || len(poster.messages[0].Streams[0].Entries) != 2 {
+		t.Errorf("Invalid messages: %v", poster.messages)
+	}
+}
+
+func TestNotifyTasks_Requeue(t *testing.T) {
+	ctx := context.WithValue(t.Context(), flag.LokiCollectTasksKey{}, true)
+
+	// Fail to push the first tasks.
+	poster := testPoster{err: errors.New("unavailable")}
+	post := func(ctx context.Context, m *Message) error {
+		err := poster.Post(ctx, m)
+		poster.err = nil
+		return err
+	}
+
+	b := NewBatcher(DefaultBatchMaxBytes, DefaultBatchMaxEntries, 0, post)
+
+	ch := make(chan *[]vmomi.Task, 1)
+	ch <- &[]vmomi.Task{{Key: "task-1", State: "success", Severity: "info"}}
+	time.AfterFunc(1500*time.Millisecond, func() { close(ch) })
+
+	NotifyTasks(ctx, ch, "test", "vc", config.DefaultConfig(), nil, b, &retryQueue[vmomi.Task]{})
+
+	if poster.Count() != 2 || len(poster.messages[1].Streams) != 1 {
+		t.Errorf("Not pushed again: %v", poster.messages)
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,54 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+type csvWriter struct {
+	w      *csv.Writer
+	fields []string
+	header bool
+}
+
+func newCSVWriter(w io.Writer, fields []string) *csvWriter {
+	return &csvWriter{
+		w:      csv.NewWriter(w),
+		fields: fields,
+	}
+}
+
+func (c *csvWriter) Write(record Record) error {
+	selected, err := record.Select(c.fields)
+	if err != nil {
+		return err
+	}
+
+	if !c.header {
+		err = c.w.Write(selected.Names())
+		if err != nil {
+			return err
+		}
+
+		c.header = true
+	}
+
+	values := make([]string, len(selected))
+	for i, f := range selected {
+		values[i] = formatValue(f.Value)
+	}
+
+	return c.w.Write(values)
+}
+
+func (c *csvWriter) Flush() error {
+	if !c.header && len(c.fields) != Empty {
+		err := c.w.Write(c.fields)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type jsonWriter struct {
+	w       io.Writer
+	fields  []string
+	written bool
+}
+
+type ndjsonWriter struct {
+	w      io.Writer
+	fields []string
+}
+
+func (j *jsonWriter) Write(record Record) error {
+	buf, err := encodeJSON(record, j.fields)
+	if err != nil {
+		return err
+	}
+
+	prefix := ",\n  "
+	if !j.written {
+		prefix = "[\n  "
+	}
+
+	_, err = io.WriteString(j.w, prefix)
+	if err != nil {
+		return err
+	}
+
+	j.written = true
+
+	_, err = j.w.Write(buf)
+	return err
+}
+
+func (j *jsonWriter) Flush() error {
+	suffix := "\n]\n"
+	if !j.written {
+		suffix = "[]\n"
+	}
+
+	_, err := io.WriteString(j.w, suffix)
+	return err
+}
+
+func (n *ndjsonWriter) Write(record Record) error {
+	buf, err := encodeJSON(record, n.fields)
+	if err != nil {
+		return err
+	}
+
+	_, err = n.w.Write(append(buf, '\n'))
+	return err
+}
+
+func (*ndjsonWriter) Flush() error {
+	return nil
+}
+
+func encodeJSON(record Record, fields []string) ([]byte, error) {
+	selected, err := record.Select(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// Encode manually to keep field order.
+	buf := []byte{'{'}
+	for i, f := range selected {
+		if i != Empty {
+			buf = append(buf, ',')
+		}
+
+		field, err := encodeJSONField(&f)
+		if err != nil {
+			return nil, err
+		}
+
+		buf = append(buf, field...)
+	}
+
+	return append(buf, '}'), nil
+}
+
+func encodeJSONField(f *Field) ([]byte, error) {
+	name, err := json.Marshal(f.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(f.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	name = append(name, ':')
+	return append(name, value...), nil
+}
@@ -0,0 +1,106 @@
+package output
+
+import (
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+const fieldKey = "key"
+
+type EventCause struct {
+	Description string   `json:"description" yaml:"description"`
+	Actions     []string `json:"actions,omitempty" yaml:"actions,omitempty"`
+}
+
+func (c EventCause) String() string {
+	return c.Description
+}
+
+type EventCauses []EventCause
+
+func (c EventCauses) String() string {
+	descriptions := make([]string, len(c))
+	for i, cause := range c {
+		descriptions[i] = cause.Description
+	}
+
+	return formatValue(descriptions)
+}
+
+func EventTextFields() []string {
+	return []string{"created_time", "user", "severity", "target", "message"}
+}
+
+// EventFields returns names of fields in event record.
+func EventFields() []string {
+	return EventRecord(&vmomi.Event{}, nil).Names()
+}
+
+// EventInfoFields returns names of fields in event info record.
+func EventInfoFields() []string {
+	return EventInfoRecord(&vmomi.EventInfo{}).Names()
+}
+
+func EventRecord(e *vmomi.Event, attributes map[string]string) Record {
+	return Record{
+		{Name: fieldKey, Value: e.Key},
+		{Name: "created_time", Value: e.CreatedTime},
+		{Name: "severity", Value: e.Severity},
+		{Name: "event_type_id", Value: e.EventTypeID},
+		{Name: "user", Value: e.UserName},
+		{Name: "chain_id", Value: e.ChainID},
+		{Name: "change_tag", Value: optional(e.ChangeTag)},
+		{Name: "task_key", Value: optional(e.TaskKey)},
+		{Name: "datacenter", Value: optional(e.Datacenter)},
+		{Name: "datacenter_id", Value: optional(e.DatacenterID)},
+		{Name: "compute_resource", Value: optional(e.ComputeResource)},
+		{Name: "compute_resource_id", Value: optional(e.ComputeResourceID)},
+		{Name: "host", Value: optional(e.Host)},
+		{Name: "host_id", Value: optional(e.HostID)},
+		{Name: "vm", Value: optional(e.VM)},
+		{Name: "vm_id", Value: optional(e.VMID)},
+		{Name: "datastore", Value: optional(e.Datastore)},
+		{Name: "datastore_id", Value: optional(e.DatastoreID)},
+		{Name: "network", Value: optional(e.Network)},
+		{Name: "network_id", Value: optional(e.NetworkID)},
+		{Name: "distributed_virtual_switch", Value: optional(e.DistributedVirtualSwitch)},
+		{
+			Name:  "distributed_virtual_switch_id",
+			Value: optional(e.DistributedVirtualSwitchID),
+		},
+		{Name: "inventory_path", Value: optional(e.InventoryPath)},
+		{Name: "folder", Value: optional(e.Folder)},
+		{Name: "resource_pool", Value: optional(e.ResourcePool)},
+		{Name: "tags", Value: e.Tags},
+		{Name: "custom_fields", Value: e.CustomFields},
+		{Name: "target", Value: e.Target()},
+		{Name: "message", Value: e.Message},
+		{Name: "messages", Value: e.Messages},
+		{Name: "attributes", Value: attributes},
+	}
+}
+
+func EventInfoRecord(i *vmomi.EventInfo) Record {
+	causes := make(EventCauses, len(i.LongDescription.Causes))
+	for j, c := range i.LongDescription.Causes {
+		causes[j] = EventCause{
+			Description: c.Description,
+			Actions:     c.Actions,
+		}
+	}
+
+	return Record{
+		{Name: fieldKey, Value: i.Key},
+		{Name: "category", Value: i.Category},
+		{Name: "description", Value: i.Description},
+		{Name: "long_description", Value: i.LongDescription.Description},
+		{Name: "causes", Value: causes},
+	}
+}
+
+func optional(value *string) any {
+	if value == nil {
+		return nil
+	}
+
+	return *value
+}
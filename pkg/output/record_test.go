@@ -0,0 +1,88 @@
+package output
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+//revive:disable:add-constant
+
+func recordValue(r Record, name string) any {
+	for _, f := range r {
+		if f.Name == name {
+			return f.Value
+		}
+	}
+
+	return nil
+}
+
+func TestEventFields_Inventory(t *testing.T) {
+	fields := EventFields()
+	for _, name := range []string{"inventory_path", "folder", "resource_pool"} {
+		if !slices.Contains(fields, name) {
+			t.Errorf("Not found field: %s", name)
+		}
+	}
+}
+
+func TestEventRecord_Inventory(t *testing.T) {
+	path := "/DC0/vm/VM0"
+	folder := "/DC0/vm"
+	pool := "/DC0/host/DC0_C0/Resources"
+	e := vmomi.Event{InventoryPath: &path, Folder: &folder, ResourcePool: &pool}
+
+	r := EventRecord(&e, nil)
+	if recordValue(r, "inventory_path") != path {
+		t.Errorf("Invalid inventory_path: %v", recordValue(r, "inventory_path"))
+	}
+
+	if recordValue(r, "folder") != folder {
+		t.Errorf("Invalid folder: %v", recordValue(r, "folder"))
+	}
+
+	if recordValue(r, "resource_pool") != pool {
+		t.Errorf("Invalid resource_pool: %v", recordValue(r, "resource_pool"))
+	}
+}
+
+func TestEventRecord_InventoryEmpty(t *testing.T) {
+	r := EventRecord(&vmomi.Event{}, nil)
+	if recordValue(r, "inventory_path") != nil {
+		t.Errorf("Invalid inventory_path: %v", recordValue(r, "inventory_path"))
+	}
+}
+
+func TestEventRecord_Tags(t *testing.T) {
+	e := vmomi.Event{Tags: map[string][]string{"team": {"infra", "ops"}, "env": {"prod"}}}
+
+	r := EventRecord(&e, nil)
+	value := formatValue(recordValue(r, "tags"))
+	if value != "env=prod;team=infra,ops" {
+		t.Errorf("Invalid tags: %s", value)
+	}
+}
+
+func TestEventRecord_CustomFields(t *testing.T) {
+	e := vmomi.Event{CustomFields: map[string]string{"owner": "alice", "cost": "100"}}
+
+	r := EventRecord(&e, nil)
+	value := formatValue(recordValue(r, "custom_fields"))
+	if value != "cost=100;owner=alice" {
+		t.Errorf("Invalid custom_fields: %s", value)
+	}
+}
+
+func TestEventRecord_Messages(t *testing.T) {
+	e := vmomi.Event{Message: "User logged in", Messages: map[string]string{"ja": "ログイン"}}
+
+	r := EventRecord(&e, nil)
+	value := formatValue(recordValue(r, "messages"))
+	if value != "ja=ログイン" {
+		t.Errorf("Invalid messages: %s", value)
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,123 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type textWriter struct {
+	w      io.Writer
+	fields []string
+}
+
+type eventInfoTextWriter struct {
+	w io.Writer
+}
+
+type logfmtWriter struct {
+	w      io.Writer
+	fields []string
+}
+
+func (t *textWriter) Write(record Record) error {
+	selected, err := record.Select(t.fields)
+	if err != nil {
+		return err
+	}
+
+	values := make([]string, len(selected))
+	for i, f := range selected {
+		if i == Empty {
+			// The first field is printed without name.
+			values[i] = fmt.Sprint(textValue(f.Value))
+			continue
+		}
+
+		values[i] = fmt.Sprintf("%s=%v", f.Name, textValue(f.Value))
+	}
+
+	_, err = fmt.Fprintln(t.w, strings.Join(values, "\t"))
+	return err
+}
+
+func (*textWriter) Flush() error {
+	return nil
+}
+
+func (t *eventInfoTextWriter) Write(record Record) error {
+	selected, err := record.Select(EventInfoFields())
+	if err != nil {
+		return err
+	}
+
+	//revive:disable:add-constant
+	lines := []string{fmt.Sprintln(selected[0].Value, selected[1].Value, selected[2].Value)}
+
+	// Long description and causes are printed in indented lines.
+	if v, ok := selected[3].Value.(string); ok && len(v) != Empty {
+		lines = append(lines, fmt.Sprintln("  Long Description:", v))
+		lines = append(lines, eventCauseLines(selected[4].Value)...)
+	}
+	//revive:enable:add-constant
+
+	_, err = fmt.Fprint(t.w, strings.Join(lines, ""))
+	return err
+}
+
+func (*eventInfoTextWriter) Flush() error {
+	return nil
+}
+
+func eventCauseLines(value any) []string {
+	causes, ok := value.(EventCauses)
+	if !ok {
+		return nil
+	}
+
+	lines := make([]string, len(causes))
+	for i, c := range causes {
+		lines[i] = fmt.Sprintln("  Cause:", c.Description)
+	}
+
+	return lines
+}
+
+func (l *logfmtWriter) Write(record Record) error {
+	selected, err := record.Select(l.fields)
+	if err != nil {
+		return err
+	}
+
+	values := make([]string, len(selected))
+	for i, f := range selected {
+		values[i] = fmt.Sprintf("%s=%s", f.Name, logfmtValue(formatValue(f.Value)))
+	}
+
+	_, err = fmt.Fprintln(l.w, strings.Join(values, " "))
+	return err
+}
+
+func (*logfmtWriter) Flush() error {
+	return nil
+}
+
+func textValue(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []string, map[string]string:
+		return formatValue(v)
+	default:
+		return v
+	}
+}
+
+func logfmtValue(value string) string {
+	if strings.ContainsAny(value, " =\"\t\r\n\\") {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
@@ -0,0 +1,160 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	FormatText   = "text"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+	FormatLogfmt = "logfmt"
+	FormatYAML   = "yaml"
+)
+
+const Empty = int(0)
+
+const ValueSeparator = ";"
+
+const ListSeparator = ","
+
+type Field struct {
+	Name  string
+	Value any
+}
+
+type Record []Field
+
+type Writer interface {
+	Write(record Record) error
+	Flush() error
+}
+
+func Formats() []string {
+	return []string{
+		FormatText,
+		FormatJSON,
+		FormatNDJSON,
+		FormatCSV,
+		FormatLogfmt,
+		FormatYAML,
+	}
+}
+
+func NewWriter(w io.Writer, format string, fields []string) (Writer, error) {
+	switch format {
+	case FormatText:
+		return &textWriter{w: w, fields: fields}, nil
+	case FormatJSON:
+		return &jsonWriter{w: w, fields: fields}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{w: w, fields: fields}, nil
+	case FormatCSV:
+		return newCSVWriter(w, fields), nil
+	case FormatLogfmt:
+		return &logfmtWriter{w: w, fields: fields}, nil
+	case FormatYAML:
+		return &yamlWriter{w: w, fields: fields}, nil
+	default:
+		return nil, fmt.Errorf("invalid output format: %s", format)
+	}
+}
+
+// NewEventInfoWriter prints event info with long description and causes in text format.
+func NewEventInfoWriter(w io.Writer, format string, fields []string) (Writer, error) {
+	if format == FormatText && len(fields) == Empty {
+		return &eventInfoTextWriter{w: w}, nil
+	}
+
+	return NewWriter(w, format, fields)
+}
+
+// ValidateFields returns error if fields contain name not in names.
+func ValidateFields(fields []string, names []string) error {
+	for _, field := range fields {
+		if !slices.Contains(names, field) {
+			return fmt.Errorf("invalid field: %s", field)
+		}
+	}
+
+	return nil
+}
+
+func (r Record) Names() []string {
+	names := make([]string, len(r))
+	for i, f := range r {
+		names[i] = f.Name
+	}
+
+	return names
+}
+
+func (r Record) Select(fields []string) (Record, error) {
+	if len(fields) == Empty {
+		return r, nil
+	}
+
+	selected := make(Record, Empty, len(fields))
+	for _, name := range fields {
+		idx := slices.IndexFunc(r, func(f Field) bool { return f.Name == name })
+		if idx < Empty {
+			return nil, fmt.Errorf("invalid field: %s", name)
+		}
+
+		selected = append(selected, r[idx])
+	}
+
+	return selected, nil
+}
+
+//revive:disable:cyclomatic
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []string:
+		return strings.Join(v, ValueSeparator)
+	case map[string]string:
+		keys := make([]string, Empty, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		values := make([]string, len(keys))
+		for i, k := range keys {
+			values[i] = fmt.Sprintf("%s=%s", k, v[k])
+		}
+
+		return strings.Join(values, ValueSeparator)
+	case map[string][]string:
+		return formatValue(joinValues(v))
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+//revive:enable:cyclomatic
+
+func joinValues(value map[string][]string) map[string]string {
+	joined := make(map[string]string, len(value))
+	for k, values := range value {
+		joined[k] = strings.Join(values, ListSeparator)
+	}
+
+	return joined
+}
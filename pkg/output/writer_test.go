@@ -0,0 +1,127 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/vmomi"
+)
+
+//revive:disable:add-constant
+
+func testRecord() Record {
+	message := "a b"
+	return Record{
+		{Name: "key", Value: int32(1)},
+		{Name: "vm", Value: nil},
+		{Name: "message", Value: message},
+	}
+}
+
+func writeRecord(t *testing.T, format string, fields []string) string {
+	var buf strings.Builder
+
+	w, err := NewWriter(&buf, format, fields)
+	if err != nil {
+		t.Fatalf("NewWriter error: %v", err)
+	}
+
+	err = w.Write(testRecord())
+	if err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+
+	err = w.Flush()
+	if err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+
+	return buf.String()
+}
+
+func TestWriter_Text(t *testing.T) {
+	out := writeRecord(t, FormatText, nil)
+	if out != "1\tvm=\tmessage=a b\n" {
+		t.Errorf("Invalid output: %q", out)
+	}
+}
+
+func TestWriter_NDJSON(t *testing.T) {
+	out := writeRecord(t, FormatNDJSON, nil)
+	if out != `{"key":1,"vm":null,"message":"a b"}`+"\n" {
+		t.Errorf("Invalid output: %q", out)
+	}
+}
+
+func TestWriter_CSVFields(t *testing.T) {
+	out := writeRecord(t, FormatCSV, []string{"message", "key"})
+	if out != "message,key\na b,1\n" {
+		t.Errorf("Invalid output: %q", out)
+	}
+}
+
+func TestWriter_Logfmt(t *testing.T) {
+	out := writeRecord(t, FormatLogfmt, nil)
+	if out != `key=1 vm= message="a b"`+"\n" {
+		t.Errorf("Invalid output: %q", out)
+	}
+}
+
+func TestWriter_InvalidField(t *testing.T) {
+	var buf strings.Builder
+
+	w, err := NewWriter(&buf, FormatJSON, []string{"unknown"})
+	if err != nil {
+		t.Fatalf("NewWriter error: %v", err)
+	}
+
+	err = w.Write(testRecord())
+	if err == nil {
+		t.Errorf("Invalid error: %v", err)
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	err := ValidateFields([]string{"key", "message"}, EventFields())
+	if err != nil {
+		t.Errorf("ValidateFields error: %v", err)
+	}
+
+	err = ValidateFields([]string{"key", "unknown"}, EventInfoFields())
+	if err == nil {
+		t.Error("Unknown field accepted")
+	}
+}
+
+func TestNewEventInfoWriter_Text(t *testing.T) {
+	var buf strings.Builder
+
+	w, err := NewEventInfoWriter(&buf, FormatText, nil)
+	if err != nil {
+		t.Fatalf("NewEventInfoWriter error: %v", err)
+	}
+
+	info := vmomi.EventInfo{
+		Key:         "VmPoweredOffEvent",
+		Category:    "info",
+		Description: "VM powered off",
+		LongDescription: vmomi.EventLongDescription{
+			Description: "The VM was powered off",
+			Causes:      []vmomi.EventCause{{Description: "User powered off the VM"}},
+		},
+	}
+
+	err = w.Write(EventInfoRecord(&info))
+	if err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+
+	expected := "VmPoweredOffEvent info VM powered off\n" +
+		"  Long Description: The VM was powered off\n" +
+		"  Cause: User powered off the VM\n"
+	if buf.String() != expected {
+		t.Errorf("Invalid output: %q", buf.String())
+	}
+}
+
+//revive:enable:add-constant
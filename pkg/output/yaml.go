@@ -0,0 +1,57 @@
+package output
+
+import (
+	"io"
+
+	"go.yaml.in/yaml/v4"
+)
+
+type yamlWriter struct {
+	w       io.Writer
+	fields  []string
+	written bool
+}
+
+func (y *yamlWriter) Write(record Record) error {
+	selected, err := record.Select(y.fields)
+	if err != nil {
+		return err
+	}
+
+	// Encode as one item sequence to keep field order and stream records.
+	item := yaml.Node{Kind: yaml.MappingNode}
+	for _, f := range selected {
+		var value yaml.Node
+		err = value.Encode(f.Value)
+		if err != nil {
+			return err
+		}
+
+		item.Content = append(
+			item.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: f.Name},
+			&value,
+		)
+	}
+
+	seq := yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{&item}}
+
+	buf, err := yaml.Marshal(&seq)
+	if err != nil {
+		return err
+	}
+
+	y.written = true
+
+	_, err = y.w.Write(buf)
+	return err
+}
+
+func (y *yamlWriter) Flush() error {
+	if y.written {
+		return nil
+	}
+
+	_, err := io.WriteString(y.w, "[]\n")
+	return err
+}
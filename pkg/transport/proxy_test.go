@@ -0,0 +1,113 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+//revive:disable:add-constant
+
+func TestIsNoProxy(t *testing.T) {
+	noProxy := []string{"vcenter01", ".example.com", "10.0.0.0/8", "loki:3100"}
+
+	cases := map[string]bool{
+		"https://vcenter01/sdk":          true,
+		"https://vcenter02/sdk":          false,
+		"https://vc.example.com/sdk":     true,
+		"https://example.com/sdk":        true,
+		"https://badexample.com/sdk":     false,
+		"https://10.1.2.3/sdk":           true,
+		"https://192.168.0.1/sdk":        false,
+		"http://loki:3100/loki/api/v1":   true,
+		"https://loki:443/loki/api/v1":   false,
+		"https://VCENTER01:443/sdk/path": true,
+	}
+
+	for rawURL, expected := range cases {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatalf("Parse error: %v", err)
+		}
+
+		if IsNoProxy(u, noProxy) != expected {
+			t.Errorf("Invalid no proxy: %s", rawURL)
+		}
+	}
+}
+
+func TestProxyOptions_ProxyFunc(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	opts := ProxyOptions{URL: proxy.URL, NoProxy: []string{"direct.invalid"}}
+
+	fn, err := opts.ProxyFunc()
+	if err != nil {
+		t.Fatalf("ProxyFunc error: %v", err)
+	}
+
+	client := http.Client{Transport: &http.Transport{Proxy: fn}}
+
+	res, err := client.Get("http://loki.invalid/ready")
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+
+	err = res.Body.Close()
+	if err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	if proxied != "http://loki.invalid/ready" {
+		t.Errorf("Invalid proxied URL: %s", proxied)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://direct.invalid/", nil)
+
+	u, err := fn(req)
+	if err != nil || u != nil {
+		t.Errorf("Invalid proxy for no proxy host: %v", u)
+	}
+}
+
+func TestProxyOptions_ProxyFunc_Empty(t *testing.T) {
+	var opts *ProxyOptions
+
+	fn, err := opts.ProxyFunc()
+	if err != nil || fn != nil {
+		t.Errorf("Invalid proxy func: %v", err)
+	}
+}
+
+func TestProxyOptions_ProxyFunc_UnknownScheme(t *testing.T) {
+	opts := ProxyOptions{URL: "ftp://proxy:21"}
+
+	_, err := opts.ProxyFunc()
+	if err == nil {
+		t.Error("Unknown scheme accepted")
+	}
+}
+
+func TestProxyOptions_ProxyFunc_NoHTTPS(t *testing.T) {
+	opts := ProxyOptions{URL: "https://proxy:3128", NoHTTPS: true}
+
+	_, err := opts.ProxyFunc()
+	if err == nil {
+		t.Error("https proxy accepted")
+	}
+
+	opts.URL = "http://proxy:3128"
+
+	proxy, err := opts.ProxyFunc()
+	if err != nil || proxy == nil {
+		t.Errorf("http proxy rejected: %v", err)
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,156 @@
+package transport
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const Empty = int(0)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSOptions configures verification of server and client certificate.
+type TLSOptions struct {
+	NoVerifySSL bool
+	// CABundle is PEM files of CA trusted in addition to system roots.
+	CABundle []string
+	// Thumbprint is SHA-256 thumbprint of server certificate trusted instead of CA.
+	Thumbprint string
+	// CertFile and KeyFile are client certificate for mutual TLS.
+	CertFile   string
+	KeyFile    string
+	MinVersion string
+	ServerName string
+}
+
+func (o *TLSOptions) Config() (*tls.Config, error) {
+	if o == nil {
+		return &tls.Config{}, nil
+	}
+
+	minVersion, err := ParseTLSVersion(o.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		InsecureSkipVerify: o.NoVerifySSL,
+		MinVersion:         minVersion,
+		ServerName:         o.ServerName,
+	}
+
+	err = configureRootCAs(config, o.CABundle)
+	if err != nil {
+		return nil, err
+	}
+
+	err = configureClientCertificate(config, o.CertFile, o.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(o.Thumbprint) != Empty {
+		// Pinned certificate is trusted even if it is not signed by CA.
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = verifyThumbprint(o.Thumbprint)
+	}
+
+	return config, nil
+}
+
+// ParseTLSVersion returns TLS version (e.g. `1.2`), or zero to use default if empty.
+func ParseTLSVersion(version string) (uint16, error) {
+	if len(version) == Empty {
+		return uint16(Empty), nil
+	}
+
+	v, ok := tlsVersions[strings.TrimPrefix(version, "TLS")]
+	if !ok {
+		return uint16(Empty), fmt.Errorf("unknown TLS version: %s", version)
+	}
+
+	return v, nil
+}
+
+// Thumbprint returns SHA-256 thumbprint of certificate
+// in same format as `openssl x509 -fingerprint -sha256`.
+func Thumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+
+	hexes := make([]string, len(sum))
+	for i, b := range sum {
+		hexes[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(hexes, ":")
+}
+
+func configureRootCAs(config *tls.Config, bundle []string) error {
+	if len(bundle) == Empty {
+		return nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	for _, path := range bundle {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("invalid CA bundle: %s", path)
+		}
+	}
+
+	config.RootCAs = pool
+	return nil
+}
+
+func configureClientCertificate(config *tls.Config, certFile string, keyFile string) error {
+	if len(certFile) == Empty {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+
+	config.Certificates = []tls.Certificate{cert}
+	return nil
+}
+
+func verifyThumbprint(thumbprint string) func(tls.ConnectionState) error {
+	expected := normalizeThumbprint(thumbprint)
+
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == Empty {
+			return errors.New("server certificate not found")
+		}
+
+		actual := Thumbprint(cs.PeerCertificates[Empty])
+		if normalizeThumbprint(actual) != expected {
+			return fmt.Errorf("server thumbprint does not match: %s", actual)
+		}
+
+		return nil
+	}
+}
+
+func normalizeThumbprint(thumbprint string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(thumbprint), ":", ""))
+}
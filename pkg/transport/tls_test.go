@@ -0,0 +1,95 @@
+package transport
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//revive:disable:add-constant
+
+func testGet(t *testing.T, server *httptest.Server, opts *TLSOptions) error {
+	t.Helper()
+
+	config, err := opts.Config()
+	if err != nil {
+		t.Fatalf("Config error: %v", err)
+	}
+
+	client := http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+
+	res, err := client.Get(server.URL)
+	if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+func TestTLSOptions_Config_Thumbprint(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	thumbprint := Thumbprint(server.Certificate())
+
+	err := testGet(t, server, &TLSOptions{Thumbprint: thumbprint})
+	if err != nil {
+		t.Errorf("Get error: %v", err)
+	}
+}
+
+func TestTLSOptions_Config_ThumbprintMismatch(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	err := testGet(t, server, &TLSOptions{NoVerifySSL: true, Thumbprint: "00:11:22"})
+	if err == nil {
+		t.Error("Connected to server of unknown thumbprint")
+	}
+}
+
+func TestTLSOptions_Config_CABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	err := testGet(t, server, &TLSOptions{})
+	if err == nil {
+		t.Fatal("Connected to server of unknown CA")
+	}
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	err = os.WriteFile(path, data, 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	err = testGet(t, server, &TLSOptions{CABundle: []string{path}, ServerName: "example.com"})
+	if err != nil {
+		t.Errorf("Get error: %v", err)
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	v, err := ParseTLSVersion("1.3")
+	if err != nil || v != tls.VersionTLS13 {
+		t.Errorf("Invalid version: %v %v", v, err)
+	}
+
+	v, err = ParseTLSVersion("")
+	if err != nil || v != 0 {
+		t.Errorf("Invalid version: %v %v", v, err)
+	}
+
+	_, err = ParseTLSVersion("1.4")
+	if err == nil {
+		t.Error("Unknown version accepted")
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,395 @@
+package vmomi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/vmware/govmomi/alarm"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	sx "github.com/9506hqwy/vmomi-event-source/pkg/vmomi/sessionex"
+)
+
+type Alarm struct {
+	Key            string
+	AlarmID        string
+	Name           string
+	Entity         string
+	EntityID       string
+	PreviousStatus string
+	Status         string
+	Time           time.Time
+	Acknowledged   bool
+	AcknowledgedBy *string
+	Severity       string
+}
+
+type alarmNames struct {
+	alarms   map[string]string
+	entities map[string]string
+}
+
+//revive:disable:cognitive-complexity
+
+func PollAlarms(
+	ctx context.Context,
+	maxWaitSeconds *int32,
+	ch chan<- *[]Alarm,
+) error {
+	c, err := login(ctx)
+	if err != nil {
+		close(ch)
+		return err
+	}
+
+	defer sx.Logout(ctx, c)
+
+	alarms, err := getAlarmDefinitions(ctx, c)
+	if err != nil {
+		close(ch)
+		return err
+	}
+
+	names := &alarmNames{
+		alarms:   alarms,
+		entities: map[string]string{},
+	}
+
+	waiter, filter, err := createAlarmStateWatcher(ctx, c)
+	if err != nil {
+		close(ch)
+		return err
+	}
+
+	defer destroyPropertyCollector(ctx, waiter)
+	defer destroyPropertyFilter(ctx, filter)
+
+	err = waitUpdateForAlarmState(
+		ctx,
+		c,
+		waiter,
+		maxWaitSeconds,
+		names,
+		func(alarms *[]Alarm) {
+			if len(*alarms) != Empty {
+				ch <- alarms
+			}
+		},
+	)
+	if err != nil {
+		close(ch)
+		return err
+	}
+
+	close(ch)
+	return nil
+}
+
+//revive:enable:cognitive-complexity
+
+func toAlarm(
+	names *alarmNames,
+	previous *types.AlarmState,
+	current *types.AlarmState,
+) Alarm {
+	state := current
+	if state == nil {
+		state = previous
+	}
+
+	model := Alarm{
+		Key:            state.Key,
+		AlarmID:        state.Alarm.Value,
+		Name:           names.getAlarmName(state.Alarm),
+		Entity:         names.getEntityName(state.Entity),
+		EntityID:       state.Entity.Value,
+		PreviousStatus: string(types.ManagedEntityStatusGreen),
+		Status:         string(types.ManagedEntityStatusGreen),
+		Time:           time.Now().UTC(),
+	}
+
+	if previous != nil {
+		model.PreviousStatus = string(previous.OverallStatus)
+	}
+
+	if current != nil {
+		model.Status = string(current.OverallStatus)
+		model.Acknowledged, model.AcknowledgedBy = getAlarmAcknowledged(current)
+	}
+
+	if current != nil && previous == nil {
+		model.Time = current.Time
+	}
+
+	model.Severity = getAlarmSeverity(current)
+
+	return model
+}
+
+func getAlarmDefinitions(ctx context.Context, c *vim25.Client) (map[string]string, error) {
+	m := alarm.NewManager(c)
+	root := object.NewRootFolder(c)
+
+	alarms, err := sx.ExecCallAPI(
+		ctx,
+		func(cctx context.Context) ([]mo.Alarm, error) {
+			return m.GetAlarm(cctx, root)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(alarms))
+	for _, a := range alarms {
+		names[a.Self.Value] = a.Info.Name
+	}
+
+	return names, nil
+}
+
+func createAlarmStateWatcher(
+	ctx context.Context,
+	c *vim25.Client,
+) (*property.Collector, *property.Filter, error) {
+	pm := property.DefaultCollector(c)
+
+	waiter, err := pm.Create(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spec := types.PropertyFilterSpec{
+		ObjectSet: []types.ObjectSpec{
+			{
+				Obj: c.ServiceContent.RootFolder,
+			},
+		},
+		PropSet: []types.PropertySpec{
+			{
+				Type:    "Folder",
+				PathSet: []string{"triggeredAlarmState"},
+			},
+		},
+	}
+
+	req := types.CreateFilter{
+		Spec:           spec,
+		PartialUpdates: false,
+	}
+
+	filter, err := waiter.CreateFilter(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return waiter, filter, nil
+}
+
+func waitUpdateForAlarmState(
+	ctx context.Context,
+	c *vim25.Client,
+	waiter *property.Collector,
+	maxWaitSeconds *int32,
+	names *alarmNames,
+	onUpdatesFn func(*[]Alarm),
+) error {
+	opt := property.WaitOptions{
+		Options: &types.WaitOptions{
+			MaxObjectUpdates: MaxObjectUpdates,
+			MaxWaitSeconds:   maxWaitSeconds,
+		},
+	}
+
+	// Triggered alarms before watching are notified at first.
+	previous := map[string]types.AlarmState{}
+
+	return waiter.WaitForUpdatesEx(ctx, &opt, func(updates []types.ObjectUpdate) bool {
+		states, ok := getTriggeredAlarmState(updates)
+		if !ok {
+			return false
+		}
+
+		names.resolve(ctx, c, states)
+
+		var alarms []Alarm
+		alarms, previous = diffAlarmState(names, previous, states)
+		onUpdatesFn(&alarms)
+
+		return false
+	})
+}
+
+//revive:disable:cognitive-complexity
+
+func getTriggeredAlarmState(updates []types.ObjectUpdate) ([]types.AlarmState, bool) {
+	for _, update := range updates {
+		for _, change := range update.ChangeSet {
+			if change.Name != "triggeredAlarmState" {
+				continue
+			}
+
+			states, ok := change.Val.(types.ArrayOfAlarmState)
+			if !ok {
+				// Property value is unset when no alarm is triggered.
+				return []types.AlarmState{}, true
+			}
+
+			return states.AlarmState, true
+		}
+	}
+
+	return nil, false
+}
+
+func diffAlarmState(
+	names *alarmNames,
+	previous map[string]types.AlarmState,
+	states []types.AlarmState,
+) ([]Alarm, map[string]types.AlarmState) {
+	alarms := []Alarm{}
+	current := make(map[string]types.AlarmState, len(states))
+
+	for _, state := range states {
+		current[state.Key] = state
+	}
+
+	for _, state := range states {
+		old, ok := previous[state.Key]
+		if !ok {
+			alarms = append(alarms, toAlarm(names, nil, &state))
+			continue
+		}
+
+		if isAlarmStateChanged(&old, &state) {
+			alarms = append(alarms, toAlarm(names, &old, &state))
+		}
+	}
+
+	for key, old := range previous {
+		if _, ok := current[key]; !ok {
+			alarms = append(alarms, toAlarm(names, &old, nil))
+		}
+	}
+
+	return alarms, current
+}
+
+//revive:enable:cognitive-complexity
+
+func isAlarmStateChanged(previous *types.AlarmState, current *types.AlarmState) bool {
+	if previous.OverallStatus != current.OverallStatus {
+		return true
+	}
+
+	previousAcknowledged, _ := getAlarmAcknowledged(previous)
+	currentAcknowledged, _ := getAlarmAcknowledged(current)
+	return previousAcknowledged != currentAcknowledged
+}
+
+func getAlarmAcknowledged(state *types.AlarmState) (bool, *string) {
+	if state.Acknowledged == nil || !*state.Acknowledged {
+		return false, nil
+	}
+
+	if state.AcknowledgedByUser == "" {
+		return true, nil
+	}
+
+	user := state.AcknowledgedByUser
+	return true, &user
+}
+
+func getAlarmSeverity(state *types.AlarmState) string {
+	if state == nil {
+		// Cleared alarm.
+		return severityInfo
+	}
+
+	switch state.OverallStatus {
+	case types.ManagedEntityStatusRed:
+		return severityError
+	case types.ManagedEntityStatusYellow:
+		return severityWarning
+	default:
+		return severityInfo
+	}
+}
+
+//revive:disable:cognitive-complexity
+
+func (n *alarmNames) resolve(ctx context.Context, c *vim25.Client, states []types.AlarmState) {
+	alarms := []types.ManagedObjectReference{}
+	entities := []types.ManagedObjectReference{}
+
+	for _, state := range states {
+		if _, ok := n.alarms[state.Alarm.Value]; !ok {
+			alarms = append(alarms, state.Alarm)
+		}
+
+		// Refresh entity name every time to follow renamed entity.
+		entities = append(entities, state.Entity)
+	}
+
+	pc := property.DefaultCollector(c)
+
+	if len(alarms) != Empty {
+		var content []mo.Alarm
+		_, err := sx.ExecCallAPI(
+			ctx,
+			func(cctx context.Context) (int, error) {
+				return 0, pc.Retrieve(cctx, alarms, []string{"info.name"}, &content)
+			},
+		)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to retrieve alarm name", "error", err)
+		}
+
+		for _, a := range content {
+			n.alarms[a.Self.Value] = a.Info.Name
+		}
+	}
+
+	if len(entities) != Empty {
+		var content []mo.ManagedEntity
+		_, err := sx.ExecCallAPI(
+			ctx,
+			func(cctx context.Context) (int, error) {
+				return 0, pc.Retrieve(cctx, entities, []string{"name"}, &content)
+			},
+		)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to retrieve entity name", "error", err)
+		}
+
+		for _, e := range content {
+			n.entities[e.Self.Value] = e.Name
+		}
+	}
+}
+
+//revive:enable:cognitive-complexity
+
+func (n *alarmNames) getAlarmName(ref types.ManagedObjectReference) string {
+	name, ok := n.alarms[ref.Value]
+	if ok {
+		return name
+	}
+
+	return ref.Value
+}
+
+func (n *alarmNames) getEntityName(ref types.ManagedObjectReference) string {
+	name, ok := n.entities[ref.Value]
+	if ok {
+		return name
+	}
+
+	return fmt.Sprintf("%s:%s", ref.Type, ref.Value)
+}
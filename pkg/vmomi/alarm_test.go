@@ -0,0 +1,188 @@
+package vmomi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+//revive:disable:add-constant
+
+func testAlarmNames() *alarmNames {
+	return &alarmNames{
+		alarms:   map[string]string{"alarm-1": "Host CPU usage"},
+		entities: map[string]string{"host-1": "esxi01"},
+	}
+}
+
+func testAlarmState(status types.ManagedEntityStatus) types.AlarmState {
+	return types.AlarmState{
+		Key:           "alarm-1.host-1",
+		Entity:        types.ManagedObjectReference{Type: "HostSystem", Value: "host-1"},
+		Alarm:         types.ManagedObjectReference{Type: "Alarm", Value: "alarm-1"},
+		OverallStatus: status,
+		Acknowledged:  types.NewBool(false),
+	}
+}
+
+func testAcknowledgedAlarmState(status types.ManagedEntityStatus) types.AlarmState {
+	state := testAlarmState(status)
+	state.Acknowledged = types.NewBool(true)
+	state.AcknowledgedByUser = "admin"
+	return state
+}
+
+func Test_diffAlarmState_Initial(t *testing.T) {
+	states := []types.AlarmState{testAlarmState(types.ManagedEntityStatusRed)}
+
+	alarms, current := diffAlarmState(testAlarmNames(), map[string]types.AlarmState{}, states)
+	if len(alarms) != 1 || len(current) != 1 {
+		t.Fatalf("Invalid alarms: %v", alarms)
+	}
+
+	alarm := alarms[0]
+	if alarm.PreviousStatus != "green" || alarm.Status != "red" || alarm.Severity != "error" {
+		t.Errorf("Invalid status: %v", alarm)
+	}
+}
+
+func Test_diffAlarmState_Triggered(t *testing.T) {
+	previous := map[string]types.AlarmState{}
+	states := []types.AlarmState{testAlarmState(types.ManagedEntityStatusYellow)}
+
+	alarms, _ := diffAlarmState(testAlarmNames(), previous, states)
+	if len(alarms) != 1 {
+		t.Fatalf("Invalid alarms: %v", alarms)
+	}
+
+	alarm := alarms[0]
+	if alarm.Name != "Host CPU usage" || alarm.Entity != "esxi01" {
+		t.Errorf("Invalid name: %v", alarm)
+	}
+
+	if alarm.PreviousStatus != "green" || alarm.Status != "yellow" || alarm.Severity != "warning" {
+		t.Errorf("Invalid status: %v", alarm)
+	}
+}
+
+func Test_diffAlarmState_Changed(t *testing.T) {
+	old := testAlarmState(types.ManagedEntityStatusYellow)
+	previous := map[string]types.AlarmState{old.Key: old}
+	states := []types.AlarmState{testAlarmState(types.ManagedEntityStatusRed)}
+
+	alarms, _ := diffAlarmState(testAlarmNames(), previous, states)
+	if len(alarms) != 1 {
+		t.Fatalf("Invalid alarms: %v", alarms)
+	}
+
+	alarm := alarms[0]
+	if alarm.PreviousStatus != "yellow" || alarm.Status != "red" || alarm.Severity != "error" {
+		t.Errorf("Invalid status: %v", alarm)
+	}
+}
+
+func Test_diffAlarmState_Acknowledged(t *testing.T) {
+	old := testAlarmState(types.ManagedEntityStatusRed)
+	previous := map[string]types.AlarmState{old.Key: old}
+	states := []types.AlarmState{testAcknowledgedAlarmState(types.ManagedEntityStatusRed)}
+
+	alarms, _ := diffAlarmState(testAlarmNames(), previous, states)
+	if len(alarms) != 1 {
+		t.Fatalf("Invalid alarms: %v", alarms)
+	}
+
+	alarm := alarms[0]
+	if !alarm.Acknowledged || alarm.AcknowledgedBy == nil || *alarm.AcknowledgedBy != "admin" {
+		t.Errorf("Invalid acknowledged: %v", alarm)
+	}
+}
+
+func Test_diffAlarmState_Cleared(t *testing.T) {
+	old := testAcknowledgedAlarmState(types.ManagedEntityStatusRed)
+	previous := map[string]types.AlarmState{old.Key: old}
+
+	alarms, current := diffAlarmState(testAlarmNames(), previous, []types.AlarmState{})
+	if len(alarms) != 1 || len(current) != 0 {
+		t.Fatalf("Invalid alarms: %v", alarms)
+	}
+
+	alarm := alarms[0]
+	if alarm.PreviousStatus != "red" || alarm.Status != "green" || alarm.Severity != "info" {
+		t.Errorf("Invalid status: %v", alarm)
+	}
+}
+
+func Test_diffAlarmState_NotChanged(t *testing.T) {
+	old := testAlarmState(types.ManagedEntityStatusRed)
+	previous := map[string]types.AlarmState{old.Key: old}
+	states := []types.AlarmState{testAlarmState(types.ManagedEntityStatusRed)}
+
+	alarms, _ := diffAlarmState(testAlarmNames(), previous, states)
+	if len(alarms) != 0 {
+		t.Errorf("Invalid alarms: %v", alarms)
+	}
+}
+
+func Test_getAlarmSeverity(t *testing.T) {
+	cases := map[types.ManagedEntityStatus]string{
+		types.ManagedEntityStatusRed:    "error",
+		types.ManagedEntityStatusYellow: "warning",
+		types.ManagedEntityStatusGreen:  "info",
+		types.ManagedEntityStatusGray:   "info",
+	}
+
+	for status, expected := range cases {
+		state := testAlarmState(status)
+		if severity := getAlarmSeverity(&state); severity != expected {
+			t.Errorf("%s: Invalid severity: %s", status, severity)
+		}
+	}
+
+	if severity := getAlarmSeverity(nil); severity != "info" {
+		t.Errorf("Invalid cleared severity: %s", severity)
+	}
+}
+
+func testRenameVM(ctx context.Context, t *testing.T, vm *object.VirtualMachine, name string) {
+	t.Helper()
+
+	task, err := vm.Rename(ctx, name)
+	if err == nil {
+		err = task.Wait(ctx)
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAlarmNames_Resolve_Renamed(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		state := testAlarmState(types.ManagedEntityStatusRed)
+		state.Entity = vm.Reference()
+		states := []types.AlarmState{state}
+
+		names := testAlarmNames()
+		names.resolve(ctx, c, states)
+
+		testRenameVM(ctx, t, vm, "renamed")
+
+		names.resolve(ctx, c, states)
+
+		if name := names.getEntityName(state.Entity); name != "renamed" {
+			t.Errorf("Invalid entity name: %s", name)
+		}
+	})
+}
+
+//revive:enable:add-constant
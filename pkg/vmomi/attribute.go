@@ -0,0 +1,155 @@
+package vmomi
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const AttributeSeparator = "."
+
+const xmlTagSeparator = ","
+
+func getEventAttributes(e types.BaseEvent) map[string]string {
+	attributes := map[string]string{}
+	walkAttribute(attributes, "", reflect.ValueOf(e))
+	return attributes
+}
+
+func walkAttribute(attributes map[string]string, name string, v reflect.Value) {
+	//revive:disable:exhaustive
+	switch v.Kind() {
+	case reflect.Invalid, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walkAttribute(attributes, name, v.Elem())
+		}
+	case reflect.Struct:
+		walkAttributeStruct(attributes, name, v)
+	case reflect.Slice, reflect.Array:
+		walkAttributeSlice(attributes, name, v)
+	default:
+		setAttribute(attributes, name, v)
+	}
+	//revive:enable:exhaustive
+}
+
+//revive:disable:cognitive-complexity
+
+func walkAttributeStruct(attributes map[string]string, name string, v reflect.Value) {
+	if walkAttributeValue(attributes, name, v.Interface()) {
+		return
+	}
+
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous {
+			// Common fields are included in event model.
+			if field.Type == reflect.TypeFor[types.Event]() ||
+				field.Type == reflect.TypeFor[types.DynamicData]() {
+				continue
+			}
+
+			walkAttribute(attributes, name, v.Field(i))
+			continue
+		}
+
+		// Unset optional fields are not included as same as SOAP message.
+		if isOmitEmpty(&field) && v.Field(i).IsZero() {
+			continue
+		}
+
+		walkAttribute(attributes, joinAttributeName(name, getAttributeName(&field)), v.Field(i))
+	}
+}
+
+//revive:enable:cognitive-complexity
+
+func walkAttributeValue(attributes map[string]string, name string, value any) bool {
+	switch v := value.(type) {
+	case time.Time:
+		attributes[name] = v.Format(time.RFC3339Nano)
+	case types.ManagedObjectReference:
+		attributes[name] = v.Value
+	case types.KeyAnyValue:
+		walkAttribute(attributes, joinAttributeName(name, v.Key), reflect.ValueOf(v.Value))
+	case types.ExtendedEventPair:
+		setAttribute(attributes, joinAttributeName(name, v.Key), reflect.ValueOf(v.Value))
+	case types.KeyValue:
+		setAttribute(attributes, joinAttributeName(name, v.Key), reflect.ValueOf(v.Value))
+	default:
+		return false
+	}
+
+	return true
+}
+
+func walkAttributeSlice(attributes map[string]string, name string, v reflect.Value) {
+	// Key-value pairs are named by key instead of index.
+	keyed := isKeyedAttribute(v.Type().Elem())
+
+	for i := range v.Len() {
+		itemName := name
+		if !keyed {
+			itemName = joinAttributeName(name, strconv.Itoa(i))
+		}
+
+		walkAttribute(attributes, itemName, v.Index(i))
+	}
+}
+
+func isKeyedAttribute(t reflect.Type) bool {
+	return t == reflect.TypeFor[types.KeyAnyValue]() ||
+		t == reflect.TypeFor[types.ExtendedEventPair]() ||
+		t == reflect.TypeFor[types.KeyValue]()
+}
+
+func setAttribute(attributes map[string]string, name string, v reflect.Value) {
+	if !v.IsValid() || len(name) == Empty {
+		return
+	}
+
+	value := fmt.Sprint(v.Interface())
+	if len(value) == Empty {
+		return
+	}
+
+	attributes[name] = value
+}
+
+//revive:disable:add-constant
+
+func getAttributeName(field *reflect.StructField) string {
+	tag := strings.Split(field.Tag.Get("xml"), xmlTagSeparator)[0]
+	if len(tag) != Empty {
+		return tag
+	}
+
+	return strings.ToLower(field.Name[:1]) + field.Name[1:]
+}
+
+func isOmitEmpty(field *reflect.StructField) bool {
+	options := strings.Split(field.Tag.Get("xml"), xmlTagSeparator)[1:]
+	return slices.Contains(options, "omitempty")
+}
+
+//revive:enable:add-constant
+
+func joinAttributeName(parent string, name string) string {
+	if len(parent) == Empty {
+		return name
+	}
+
+	return parent + AttributeSeparator + name
+}
@@ -0,0 +1,131 @@
+package vmomi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+//revive:disable:add-constant
+
+func Test_getEventAttributes_VmMigratedEvent(t *testing.T) {
+	e := &types.VmMigratedEvent{
+		VmEvent: types.VmEvent{
+			Event: types.Event{
+				Key:      1,
+				UserName: "admin",
+			},
+			Template: false,
+		},
+		SourceHost: types.HostEventArgument{
+			EntityEventArgument: types.EntityEventArgument{Name: "esxi01"},
+			Host:                types.ManagedObjectReference{Type: "HostSystem", Value: "host-1"},
+		},
+	}
+
+	attributes := getEventAttributes(e)
+
+	if attributes["sourceHost.name"] != "esxi01" {
+		t.Errorf("Invalid sourceHost.name: %v", attributes)
+	}
+
+	if attributes["sourceHost.host"] != "host-1" {
+		t.Errorf("Invalid sourceHost.host: %v", attributes)
+	}
+
+	if attributes["template"] != "false" {
+		t.Errorf("Invalid template: %v", attributes)
+	}
+
+	if _, ok := attributes["userName"]; ok {
+		t.Errorf("Invalid userName: %v", attributes)
+	}
+}
+
+func Test_getEventAttributes_UserLoginSessionEvent(t *testing.T) {
+	e := &types.UserLoginSessionEvent{
+		IpAddress: "192.168.0.1",
+		Locale:    "en",
+		SessionId: "52",
+	}
+
+	attributes := getEventAttributes(e)
+
+	if attributes["ipAddress"] != "192.168.0.1" || attributes["sessionId"] != "52" {
+		t.Errorf("Invalid attributes: %v", attributes)
+	}
+
+	if _, ok := attributes["userAgent"]; ok {
+		t.Errorf("Invalid userAgent: %v", attributes)
+	}
+}
+
+func Test_getEventAttributes_EventEx(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := &types.EventEx{
+		EventTypeId: "com.vmware.vc.test",
+		Arguments: []types.KeyAnyValue{
+			{Key: "vm", Value: "vm01"},
+			{Key: "count", Value: int32(3)},
+			{Key: "time", Value: created},
+		},
+	}
+
+	attributes := getEventAttributes(e)
+
+	if attributes["arguments.vm"] != "vm01" || attributes["arguments.count"] != "3" {
+		t.Errorf("Invalid arguments: %v", attributes)
+	}
+
+	if attributes["arguments.time"] != "2025-01-01T00:00:00Z" {
+		t.Errorf("Invalid arguments.time: %v", attributes)
+	}
+
+	if attributes["eventTypeId"] != "com.vmware.vc.test" {
+		t.Errorf("Invalid eventTypeId: %v", attributes)
+	}
+}
+
+func Test_getEventAttributes_ExtendedEvent(t *testing.T) {
+	e := &types.ExtendedEvent{
+		EventTypeId:   "com.vmware.test",
+		ManagedObject: types.ManagedObjectReference{Type: "VirtualMachine", Value: "vm-1"},
+		Data: []types.ExtendedEventPair{
+			{Key: "reason", Value: "test"},
+		},
+	}
+
+	attributes := getEventAttributes(e)
+
+	if attributes["data.reason"] != "test" || attributes["managedObject"] != "vm-1" {
+		t.Errorf("Invalid attributes: %v", attributes)
+	}
+}
+
+func Test_getEventAttributes_VmReconfiguredEvent(t *testing.T) {
+	e := &types.VmReconfiguredEvent{
+		ConfigSpec: types.VirtualMachineConfigSpec{
+			NumCPUs: 2,
+		},
+		ConfigChanges: &types.ChangesInfoEventArgument{
+			Modified: "config.hardware.numCPU: 1 -> 2;",
+		},
+	}
+
+	attributes := getEventAttributes(e)
+
+	if attributes["configChanges.modified"] != "config.hardware.numCPU: 1 -> 2;" {
+		t.Errorf("Invalid configChanges.modified: %v", attributes)
+	}
+
+	if attributes["configSpec.numCPUs"] != "2" {
+		t.Errorf("Invalid configSpec.numCPUs: %v", attributes)
+	}
+
+	if _, ok := attributes["configSpec.memoryMB"]; ok {
+		t.Errorf("Invalid configSpec.memoryMB: %v", attributes)
+	}
+}
+
+//revive:enable:add-constant
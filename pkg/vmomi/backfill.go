@@ -0,0 +1,179 @@
+package vmomi
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/vmware/govmomi/event"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	sx "github.com/9506hqwy/vmomi-event-source/pkg/vmomi/sessionex"
+)
+
+//revive:disable:cognitive-complexity
+
+func backfillEvents(
+	ctx context.Context,
+	c *vim25.Client,
+	em *event.Manager,
+	catalog *Catalog,
+	previous *Checkpoint,
+	events *[]Event,
+	ch chan<- *[]Event,
+) error {
+	//revive:disable:add-constant
+	oldest := (*events)[0]
+	//revive:enable:add-constant
+	if !previous.CreatedTime.Before(oldest.CreatedTime) {
+		return nil
+	}
+
+	begin := previous.CreatedTime
+	end := oldest.CreatedTime
+	collector, filter, err := createEventCollectorByTime(ctx, em, &begin, &end)
+	if err != nil {
+		return err
+	}
+
+	defer destroyEventCollector(ctx, collector)
+
+	e, err := getEventManager(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	// Skip events that are delivered from the latest page.
+	known := make(map[int32]bool, len(*events))
+	for _, evt := range *events {
+		known[evt.Key] = true
+	}
+
+	var first *Event
+	count := Empty
+	err = readEventPages(ctx, e, catalog, collector, func(page *[]Event) bool {
+		targets := filterBackfill(previous, known, page)
+		if len(*targets) == Empty {
+			return true
+		}
+
+		if first == nil {
+			//revive:disable:add-constant
+			f := (*targets)[0]
+			//revive:enable:add-constant
+			first = &f
+		}
+
+		count += len(*targets)
+		ch <- targets
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "Backfilled events", "count", count, "since", previous.CreatedTime)
+
+	if first == nil {
+		first = &oldest
+	}
+
+	logBackfillGap(ctx, filter, previous, first)
+
+	return nil
+}
+
+func readEventPages(
+	ctx context.Context,
+	e *mo.EventManager,
+	catalog *Catalog,
+	collector *event.HistoryCollector,
+	fn func(*[]Event) bool,
+) error {
+	_, err := sx.ExecCallAPI(
+		ctx,
+		func(cctx context.Context) (int, error) {
+			return 0, collector.Rewind(cctx)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return readNextEventPages(ctx, e, catalog, collector, fn)
+}
+
+func readNextEventPages(
+	ctx context.Context,
+	e *mo.EventManager,
+	catalog *Catalog,
+	collector *event.HistoryCollector,
+	fn func(*[]Event) bool,
+) error {
+	for {
+		evts, err := sx.ExecCallAPI(
+			ctx,
+			func(cctx context.Context) ([]types.BaseEvent, error) {
+				return collector.ReadNextEvents(cctx, MaxEventCount)
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		if len(evts) == Empty {
+			return nil
+		}
+
+		es := ToEvents(e, catalog, &evts)
+		if !fn(&es) {
+			return nil
+		}
+	}
+}
+
+//revive:enable:cognitive-complexity
+
+func filterBackfill(previous *Checkpoint, known map[int32]bool, events *[]Event) *[]Event {
+	targets := filterAfterTime(previous, events)
+
+	filtered := make([]Event, Empty, len(*targets))
+	for _, e := range *targets {
+		if !known[e.Key] {
+			filtered = append(filtered, e)
+		}
+	}
+
+	return &filtered
+}
+
+func logBackfillGap(
+	ctx context.Context,
+	filter *types.EventFilterSpec,
+	previous *Checkpoint,
+	first *Event,
+) {
+	if isFilteredEvent(filter) {
+		// Could not estimate because key is not sequential.
+		return
+	}
+
+	if first.Key <= previous.Key {
+		// Could not estimate because key sequence is reset.
+		return
+	}
+
+	//revive:disable:add-constant
+	gap := first.Key - previous.Key - 1
+	//revive:enable:add-constant
+	if gap > int32(Empty) {
+		slog.WarnContext(
+			ctx,
+			"Could not recover events",
+			"count", gap,
+			"after_key", previous.Key,
+			"before_key", first.Key,
+		)
+	}
+}
@@ -0,0 +1,156 @@
+package vmomi
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+const DefaultCatalogTTLSeconds = 3600
+
+// CatalogKey identifies the catalog of module in locale per vCenter.
+type CatalogKey struct {
+	VCenter string
+	Module  string
+	Locale  string
+}
+
+// CatalogCache caches parsed localization catalogs and is safe for concurrent use.
+type CatalogCache struct {
+	mu      sync.RWMutex
+	modules map[CatalogKey]*catalogModule
+}
+
+type catalogModule struct {
+	values map[string]string
+	loaded time.Time
+}
+
+// Catalog is values of module catalogs for one vCenter and locale.
+type Catalog struct {
+	modules map[string]map[string]string
+	// Module of extension event type to look up values.
+	eventModules map[string]string
+	loaded       time.Time
+}
+
+var catalogCache = NewCatalogCache()
+
+func NewCatalogCache() *CatalogCache {
+	return &CatalogCache{
+		modules: map[CatalogKey]*catalogModule{},
+	}
+}
+
+// Get returns the catalog values, and fetches catalog if not cached or expired.
+// The expired values are returned if fetching fails.
+func (cc *CatalogCache) Get(
+	key CatalogKey,
+	ttl time.Duration,
+	fetch func() (*string, error),
+) (map[string]string, error) {
+	cc.mu.RLock()
+	cached, ok := cc.modules[key]
+	cc.mu.RUnlock()
+
+	if ok && !isCatalogExpired(cached.loaded, ttl) {
+		return cached.values, nil
+	}
+
+	text, err := fetch()
+	if err != nil {
+		if ok {
+			slog.Warn(
+				"Failed to refresh catalog",
+				"module", key.Module,
+				"locale", key.Locale,
+				"error", err,
+			)
+			return cached.values, nil
+		}
+
+		return nil, err
+	}
+
+	module := &catalogModule{
+		values: parseCatalog(text),
+		loaded: time.Now(),
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.modules[key] = module
+	return module.values, nil
+}
+
+func NewCatalog(values map[string]string) *Catalog {
+	return NewModuleCatalog(map[string]map[string]string{"": values}, nil)
+}
+
+func NewModuleCatalog(
+	modules map[string]map[string]string,
+	eventModules map[string]string,
+) *Catalog {
+	return &Catalog{
+		modules:      modules,
+		eventModules: eventModules,
+		loaded:       time.Now(),
+	}
+}
+
+// Value returns the value in any module. The modules are searched in order of name.
+func (c *Catalog) Value(key string) *string {
+	if c == nil {
+		return nil
+	}
+
+	for _, module := range slices.Sorted(maps.Keys(c.modules)) {
+		value := getCatalogValue(c.modules[module], key)
+		if value != nil {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// EventValue returns the value of event type in the module registering the event type.
+func (c *Catalog) EventValue(eventTypeID string, name string) *string {
+	if c == nil {
+		return nil
+	}
+
+	key := eventTypeID + AttributeSeparator + name
+
+	module, ok := c.eventModules[eventTypeID]
+	if ok {
+		return getCatalogValue(c.modules[module], key)
+	}
+
+	return c.Value(key)
+}
+
+func getCatalogValue(values map[string]string, key string) *string {
+	value, ok := values[key]
+	if ok && len(value) != Empty {
+		return &value
+	}
+
+	return nil
+}
+
+func (c *Catalog) IsExpired(ttl time.Duration) bool {
+	if c == nil {
+		return true
+	}
+
+	return isCatalogExpired(c.loaded, ttl)
+}
+
+func isCatalogExpired(loaded time.Time, ttl time.Duration) bool {
+	// Never expired if TTL is not positive.
+	return ttl > time.Duration(Empty) && time.Since(loaded) >= ttl
+}
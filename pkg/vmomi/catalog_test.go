@@ -0,0 +1,119 @@
+package vmomi
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+//revive:disable:add-constant
+
+func testCatalogFetch(text string, count *int) func() (*string, error) {
+	return func() (*string, error) {
+		*count++
+		return &text, nil
+	}
+}
+
+func TestCatalogCache_Get_Key(t *testing.T) {
+	cc := NewCatalogCache()
+	count := 0
+
+	a := CatalogKey{VCenter: "vc01", Module: "hostdiag", Locale: "en"}
+	b := CatalogKey{VCenter: "vc02", Module: "hostdiag", Locale: "en"}
+
+	_, _ = cc.Get(a, time.Hour, testCatalogFetch("a.key = \"A\"", &count))
+	_, _ = cc.Get(a, time.Hour, testCatalogFetch("a.key = \"X\"", &count))
+
+	values, err := cc.Get(b, time.Hour, testCatalogFetch("a.key = \"B\"", &count))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 2 || values["a.key"] != "B" {
+		t.Errorf("Invalid values: %v, %v", count, values)
+	}
+}
+
+func TestCatalogCache_Get_Expired(t *testing.T) {
+	cc := NewCatalogCache()
+	count := 0
+
+	key := CatalogKey{VCenter: "vc01", Module: "hostdiag", Locale: "en"}
+
+	_, _ = cc.Get(key, time.Nanosecond, testCatalogFetch("a.key = \"A\"", &count))
+	time.Sleep(time.Millisecond)
+
+	values, err := cc.Get(key, time.Nanosecond, testCatalogFetch("a.key = \"B\"", &count))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 2 || values["a.key"] != "B" {
+		t.Errorf("Invalid values: %v, %v", count, values)
+	}
+}
+
+func TestCatalogCache_Get_Stale(t *testing.T) {
+	cc := NewCatalogCache()
+	count := 0
+
+	key := CatalogKey{VCenter: "vc01", Module: "hostdiag", Locale: "en"}
+
+	_, _ = cc.Get(key, time.Nanosecond, testCatalogFetch("a.key = \"A\"", &count))
+	time.Sleep(time.Millisecond)
+
+	values, err := cc.Get(key, time.Nanosecond, func() (*string, error) {
+		return nil, errors.New("unavailable")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if values["a.key"] != "A" {
+		t.Errorf("Invalid values: %v", values)
+	}
+}
+
+func TestCatalog_Value_Nil(t *testing.T) {
+	var c *Catalog
+
+	if c.Value("a.key") != nil {
+		t.Errorf("Invalid value: %v", c.Value("a.key"))
+	}
+
+	if !c.IsExpired(time.Hour) {
+		t.Errorf("Invalid expired: %v", c.IsExpired(time.Hour))
+	}
+}
+
+func testModuleCatalog() *Catalog {
+	return NewModuleCatalog(
+		map[string]map[string]string{
+			"com.example.a": {"com.example.event.category": "info"},
+			"com.example.b": {"com.example.event.category": "error"},
+		},
+		map[string]string{"com.example.event": "com.example.b"},
+	)
+}
+
+func TestCatalog_EventValue_Module(t *testing.T) {
+	value := testModuleCatalog().EventValue("com.example.event", "category")
+
+	if value == nil || *value != "error" {
+		t.Errorf("Invalid value: %v", value)
+	}
+}
+
+func TestCatalog_EventValue_NotRegistered(t *testing.T) {
+	c := testModuleCatalog()
+	c.eventModules = nil
+
+	value := c.EventValue("com.example.event", "category")
+
+	if value == nil || *value != "info" {
+		t.Errorf("Invalid value: %v", value)
+	}
+}
+
+//revive:enable:add-constant
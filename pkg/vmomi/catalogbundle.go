@@ -0,0 +1,196 @@
+package vmomi
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+	sx "github.com/9506hqwy/vmomi-event-source/pkg/vmomi/sessionex"
+)
+
+// CatalogBundle is offline catalogs used instead of downloading from vCenter.
+type CatalogBundle struct {
+	Catalogs []BundledCatalog `json:"catalogs"`
+}
+
+type BundledCatalog struct {
+	Module string `json:"module"`
+	Locale string `json:"locale"`
+	Text   string `json:"text"`
+}
+
+// Bundles are loaded once because these are not changed while running.
+var catalogBundles sync.Map
+
+func LoadCatalogBundle(path string) (*CatalogBundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var bundle CatalogBundle
+	err = json.Unmarshal(data, &bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bundle, nil
+}
+
+// ExportCatalogBundle downloads catalogs of all modules in locales.
+// The session locale is used if locales is empty.
+func ExportCatalogBundle(ctx context.Context, locales []string) (*CatalogBundle, error) {
+	c, err := login(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer sx.Logout(ctx, c)
+
+	locales, err = getExportLocales(ctx, c, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	lm, err := GetLocalizationManager(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	ex, err := GetExtensionManager(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := CatalogBundle{
+		Catalogs: []BundledCatalog{},
+	}
+
+	for _, locale := range locales {
+		catalogs, err := exportCatalogLocale(ctx, c, lm, ex, locale)
+		if err != nil {
+			return nil, err
+		}
+
+		bundle.Catalogs = append(bundle.Catalogs, catalogs...)
+	}
+
+	return &bundle, nil
+}
+
+func getExportLocales(ctx context.Context, c *vim25.Client, locales []string) ([]string, error) {
+	if len(locales) != Empty {
+		return locales, nil
+	}
+
+	locale, err := sx.GetLocale(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{*locale}, nil
+}
+
+func exportCatalogLocale(
+	ctx context.Context,
+	c *vim25.Client,
+	lm *mo.LocalizationManager,
+	ex *mo.ExtensionManager,
+	locale string,
+) ([]BundledCatalog, error) {
+	catalogs, err := fetchCatalogModules(
+		getCatalogModules(ex),
+		func(module string) (*BundledCatalog, error) {
+			return exportCatalogModule(ctx, c, lm, locale, module)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := []BundledCatalog{}
+	for _, catalog := range catalogs {
+		if catalog != nil {
+			exported = append(exported, *catalog)
+		}
+	}
+
+	return exported, nil
+}
+
+func exportCatalogModule(
+	ctx context.Context,
+	c *vim25.Client,
+	lm *mo.LocalizationManager,
+	locale string,
+	moduleName string,
+) (*BundledCatalog, error) {
+	uri := getLocalizationCatalogURI(lm, locale, moduleName)
+	if uri == nil {
+		return nil, nil
+	}
+
+	key := CatalogKey{
+		VCenter: c.URL().Host,
+		Module:  moduleName,
+		Locale:  locale,
+	}
+
+	text, err := getLocalizationCatalog(ctx, c, *uri, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BundledCatalog{
+		Module: moduleName,
+		Locale: locale,
+		Text:   *text,
+	}, nil
+}
+
+func getCatalogBundle(ctx context.Context) (*CatalogBundle, error) {
+	path, ok := ctx.Value(flag.TargetCatalogBundleKey{}).(string)
+	if !ok || len(path) == Empty {
+		return nil, nil
+	}
+
+	cached, ok := catalogBundles.Load(path)
+	if ok {
+		bundle, ok := cached.(*CatalogBundle)
+		if ok {
+			return bundle, nil
+		}
+	}
+
+	bundle, err := LoadCatalogBundle(path)
+	if err != nil {
+		return nil, err
+	}
+
+	catalogBundles.Store(path, bundle)
+	return bundle, nil
+}
+
+// Find returns the catalog text in locale, or the language, or default locale.
+func (b *CatalogBundle) Find(locale string, moduleName string) *string {
+	if b == nil {
+		return nil
+	}
+
+	for _, l := range getCatalogLocales(locale) {
+		idx := slices.IndexFunc(b.Catalogs, func(catalog BundledCatalog) bool {
+			return catalog.Locale == l && catalog.Module == moduleName
+		})
+		if idx >= Empty {
+			return &b.Catalogs[idx].Text
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package vmomi
+
+import (
+	"testing"
+)
+
+//revive:disable:add-constant
+
+func TestCatalogBundle_Find(t *testing.T) {
+	bundle := CatalogBundle{
+		Catalogs: []BundledCatalog{
+			{Module: "hostdiag", Locale: "en", Text: "en"},
+			{Module: "hostdiag", Locale: "ja", Text: "ja"},
+		},
+	}
+
+	text := bundle.Find("ja_JP", "hostdiag")
+	if text == nil || *text != "ja" {
+		t.Errorf("Invalid catalog: %v", text)
+	}
+
+	text = bundle.Find("fr", "hostdiag")
+	if text == nil || *text != "en" {
+		t.Errorf("Invalid catalog: %v", text)
+	}
+
+	text = bundle.Find("en", "com.example")
+	if text != nil {
+		t.Errorf("Invalid catalog: %v", text)
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,98 @@
+package vmomi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/atomicfile"
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+)
+
+const catalogStoreDirMode = 0o750
+
+var catalogPathReplacer = strings.NewReplacer("/", "_", "\\", "_", ":", "_")
+
+// CatalogStore persists downloaded catalogs to directory
+// to validate by ETag and Last-Modified after restart.
+type CatalogStore struct {
+	dir string
+}
+
+// StoredCatalog is downloaded catalog and its validators.
+type StoredCatalog struct {
+	Text         string `json:"text"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func NewCatalogStore(dir string) *CatalogStore {
+	return &CatalogStore{
+		dir: dir,
+	}
+}
+
+func GetCatalogStore(ctx context.Context) *CatalogStore {
+	dir, ok := ctx.Value(flag.TargetCatalogCacheDirKey{}).(string)
+	if !ok || len(dir) == Empty {
+		return nil
+	}
+
+	return NewCatalogStore(dir)
+}
+
+func (s *CatalogStore) Load(key CatalogKey) (*StoredCatalog, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var stored StoredCatalog
+	err = json.Unmarshal(data, &stored)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+func (s *CatalogStore) Save(key CatalogKey, stored *StoredCatalog) error {
+	if s == nil || stored == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	path := s.path(key)
+
+	err = os.MkdirAll(filepath.Dir(path), catalogStoreDirMode)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(path, data)
+}
+
+func (s *CatalogStore) path(key CatalogKey) string {
+	return filepath.Join(
+		s.dir,
+		catalogPathReplacer.Replace(key.VCenter),
+		catalogPathReplacer.Replace(key.Locale),
+		catalogPathReplacer.Replace(key.Module)+".json",
+	)
+}
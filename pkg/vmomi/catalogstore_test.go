@@ -0,0 +1,107 @@
+package vmomi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+)
+
+//revive:disable:add-constant
+
+func TestCatalogStore_SaveLoad(t *testing.T) {
+	store := NewCatalogStore(t.TempDir())
+	key := CatalogKey{VCenter: "vc01:443", Module: "hostdiag", Locale: "en"}
+	stored := StoredCatalog{Text: "a.key = \"A\"", ETag: "\"1\""}
+
+	err := store.Save(key, &stored)
+	if err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	loaded, err := store.Load(key)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	if loaded == nil || *loaded != stored {
+		t.Errorf("Invalid catalog: %v", loaded)
+	}
+}
+
+func TestCatalogStore_LoadNotExist(t *testing.T) {
+	store := NewCatalogStore(t.TempDir())
+	key := CatalogKey{VCenter: "vc01", Module: "hostdiag", Locale: "en"}
+
+	loaded, err := store.Load(key)
+	if err != nil || loaded != nil {
+		t.Errorf("Invalid catalog: %v %v", loaded, err)
+	}
+}
+
+func testCatalogServer(requests *int, notModified *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.Header.Get("If-None-Match") == "\"1\"" {
+			*notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", "\"1\"")
+		_, _ = w.Write([]byte("a.key = \"A\""))
+	}))
+}
+
+func testGetLocalizationCatalog(
+	ctx context.Context,
+	t *testing.T,
+	c *vim25.Client,
+	key CatalogKey,
+) {
+	t.Helper()
+
+	text, err := getLocalizationCatalog(ctx, c, "/catalog/hostdiag.vmsg", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *text != "a.key = \"A\"" {
+		t.Errorf("Invalid catalog: %v", *text)
+	}
+}
+
+func Test_getLocalizationCatalog_NotModified(t *testing.T) {
+	requests := 0
+	notModified := 0
+	server := testCatalogServer(&requests, &notModified)
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &vim25.Client{Client: soap.NewClient(u, true)}
+
+	ctx := context.WithValue(t.Context(), flag.TargetNoVerifySSLKey{}, true)
+	ctx = context.WithValue(ctx, flag.TargetCatalogCacheDirKey{}, t.TempDir())
+
+	key := CatalogKey{VCenter: u.Host, Module: "hostdiag", Locale: "en"}
+
+	// Download and validate by ETag.
+	testGetLocalizationCatalog(ctx, t, c, key)
+	testGetLocalizationCatalog(ctx, t, c, key)
+
+	if requests != 2 || notModified != 1 {
+		t.Errorf("Invalid requests: %v, %v", requests, notModified)
+	}
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,228 @@
+package vmomi
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+)
+
+const MinChainEventCount = 2
+
+// Wait events following task completion.
+const ChainCloseDelay = 5 * time.Second
+
+const (
+	chainOutcomeError   = "error"
+	chainOutcomeSuccess = "success"
+	chainOutcomeWarning = "warning"
+)
+
+type Chain struct {
+	ChainID          int32
+	TaskKey          *string
+	Target           string
+	FirstEventTypeID string
+	LastEventTypeID  string
+	FirstTime        time.Time
+	LastTime         time.Time
+	EventCount       int
+	Outcome          string
+	Severity         string
+}
+
+func (c Chain) Duration() time.Duration {
+	return c.LastTime.Sub(c.FirstTime)
+}
+
+type ChainCorrelator struct {
+	mu        sync.Mutex
+	idle      time.Duration
+	chains    map[int32]*chainState
+	closed    map[int32]*chainState
+	completed map[string]time.Time
+	severity  func(eventTypeID string, category string) string
+}
+
+type chainState struct {
+	chain   Chain
+	keys    map[int32]struct{}
+	updated time.Time
+}
+
+func NewChainCorrelator(
+	idle time.Duration,
+	severity func(eventTypeID string, category string) string,
+) *ChainCorrelator {
+	return &ChainCorrelator{
+		idle:      idle,
+		chains:    map[int32]*chainState{},
+		closed:    map[int32]*chainState{},
+		completed: map[string]time.Time{},
+		severity:  severity,
+	}
+}
+
+func (c *ChainCorrelator) Enrich(events *[]Event) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for idx := range *events {
+		c.add(&(*events)[idx], now)
+	}
+}
+
+func (c *ChainCorrelator) CompleteTasks(tasks []Task, now time.Time) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, task := range tasks {
+		c.completed[task.Key] = now
+	}
+}
+
+func (c *ChainCorrelator) Close(now time.Time) []Chain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	chains := []Chain{}
+	for id, state := range c.chains {
+		if !c.isClosed(state, now) {
+			continue
+		}
+
+		delete(c.chains, id)
+
+		// Keep closed chain to ignore events polled again.
+		state.updated = now
+		c.closed[id] = state
+
+		// Chain of single event is same as event itself.
+		if state.chain.EventCount >= MinChainEventCount {
+			chains = append(chains, state.chain)
+		}
+	}
+
+	c.purgeCompleted(now)
+
+	slices.SortFunc(chains, func(a, b Chain) int {
+		return cmp.Compare(a.ChainID, b.ChainID)
+	})
+
+	return chains
+}
+
+func (c *ChainCorrelator) purgeCompleted(now time.Time) {
+	// Task may complete before the events are collected.
+	for key, completed := range c.completed {
+		if now.Sub(completed) >= c.idle {
+			delete(c.completed, key)
+		}
+	}
+
+	for id, state := range c.closed {
+		if now.Sub(state.updated) >= c.idle {
+			delete(c.closed, id)
+		}
+	}
+}
+
+func (c *ChainCorrelator) isClosed(state *chainState, now time.Time) bool {
+	if now.Sub(state.updated) >= c.idle {
+		return true
+	}
+
+	if state.chain.TaskKey == nil || now.Sub(state.updated) < ChainCloseDelay {
+		return false
+	}
+
+	completed, ok := c.completed[*state.chain.TaskKey]
+	return ok && now.Sub(completed) >= ChainCloseDelay
+}
+
+func (c *ChainCorrelator) add(e *Event, now time.Time) {
+	state, closed := c.closed[e.ChainID]
+	if !closed {
+		state = c.open(e)
+	}
+
+	// Event polled again after push failure is counted once.
+	_, seen := state.keys[e.Key]
+	if !closed && !seen {
+		state.keys[e.Key] = struct{}{}
+		state.updated = now
+		state.chain.append(e, c.severity(e.EventTypeID, e.Severity))
+	}
+
+	if e.TaskKey == nil {
+		e.TaskKey = state.chain.TaskKey
+	}
+}
+
+func (c *ChainCorrelator) open(e *Event) *chainState {
+	state, ok := c.chains[e.ChainID]
+	if !ok {
+		state = &chainState{
+			chain: Chain{
+				ChainID:          e.ChainID,
+				FirstEventTypeID: e.EventTypeID,
+				FirstTime:        e.CreatedTime,
+				Outcome:          chainOutcomeSuccess,
+			},
+			keys: map[int32]struct{}{},
+		}
+		c.chains[e.ChainID] = state
+	}
+
+	return state
+}
+
+func (c *Chain) append(e *Event, severity string) {
+	c.LastEventTypeID = e.EventTypeID
+	c.LastTime = e.CreatedTime
+	c.EventCount++
+
+	if c.TaskKey == nil {
+		c.TaskKey = e.TaskKey
+	}
+
+	if len(c.Target) == Empty {
+		c.Target = e.Target()
+	}
+
+	// Outcome follows overridden severity.
+	switch config.ToLevel(severity) {
+	case config.LevelCritical, config.LevelError:
+		c.Outcome = chainOutcomeError
+	case config.LevelWarn:
+		if c.Outcome != chainOutcomeError {
+			c.Outcome = chainOutcomeWarning
+		}
+	default:
+		// Keep worst outcome in chain.
+	}
+
+	c.Severity = getChainSeverity(c.Outcome)
+}
+
+func getChainSeverity(outcome string) string {
+	switch outcome {
+	case chainOutcomeError:
+		return severityError
+	case chainOutcomeWarning:
+		return severityWarning
+	default:
+		return severityInfo
+	}
+}
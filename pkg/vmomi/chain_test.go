@@ -0,0 +1,221 @@
+package vmomi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+)
+
+//revive:disable:add-constant
+
+func testChainEvents() []Event {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return []Event{
+		{
+			Key:         10,
+			ChainID:     10,
+			CreatedTime: created,
+			EventTypeID: "TaskEvent",
+			Severity:    severityInfo,
+			TaskKey:     testString("task-1"),
+			VM:          testString("vm01"),
+			VMID:        testString("vm-1"),
+		},
+		{
+			Key:         11,
+			ChainID:     10,
+			CreatedTime: created.Add(5 * time.Second),
+			EventTypeID: "VmBeingHotMigratedEvent",
+			Severity:    severityInfo,
+			VM:          testString("vm01"),
+			VMID:        testString("vm-1"),
+		},
+		{
+			Key:         12,
+			ChainID:     12,
+			CreatedTime: created.Add(6 * time.Second),
+			EventTypeID: "UserLoginSessionEvent",
+			Severity:    severityInfo,
+		},
+		{
+			Key:         13,
+			ChainID:     10,
+			CreatedTime: created.Add(30 * time.Second),
+			EventTypeID: "VmFailedMigrateEvent",
+			Severity:    severityError,
+			VM:          testString("vm01"),
+			VMID:        testString("vm-1"),
+		},
+	}
+}
+
+func TestChainCorrelator_Enrich_TaskKey(t *testing.T) {
+	c := NewChainCorrelator(time.Minute, config.DefaultSeverityConfig().GetSeverity)
+	events := testChainEvents()
+
+	c.Enrich(&events)
+
+	if events[1].TaskKey == nil || *events[1].TaskKey != "task-1" {
+		t.Errorf("Invalid task key: %v", events[1].TaskKey)
+	}
+
+	if events[2].TaskKey != nil {
+		t.Errorf("Invalid task key: %v", events[2].TaskKey)
+	}
+
+	chains := c.Close(time.Now().Add(time.Minute))
+	if len(chains) != 1 || chains[0].TaskKey == nil || *chains[0].TaskKey != "task-1" {
+		t.Errorf("Invalid chains: %v", chains)
+	}
+}
+
+func TestChainCorrelator_Close_Idle(t *testing.T) {
+	c := NewChainCorrelator(time.Minute, config.DefaultSeverityConfig().GetSeverity)
+	events := testChainEvents()
+
+	c.Enrich(&events)
+
+	chains := c.Close(time.Now().Add(time.Minute))
+	if len(chains) != 1 {
+		t.Fatalf("Invalid chains: %v", chains)
+	}
+
+	chain := chains[0]
+	if chain.ChainID != 10 ||
+		chain.EventCount != 3 ||
+		chain.FirstEventTypeID != "TaskEvent" ||
+		chain.LastEventTypeID != "VmFailedMigrateEvent" {
+		t.Errorf("Invalid chain: %v", chain)
+	}
+
+	if chain.Duration() != 30*time.Second {
+		t.Errorf("Invalid duration: %v", chain.Duration())
+	}
+
+	if chain.Outcome != chainOutcomeError || chain.Severity != severityError {
+		t.Errorf("Invalid outcome: %v", chain.Outcome)
+	}
+}
+
+func TestChainCorrelator_Close_SeverityOverride(t *testing.T) {
+	cfg := config.SeverityConfig{
+		Severity: &config.Severity{
+			Overrides: []config.SeverityOverride{
+				{EventTypeID: "VmFailedMigrateEvent", Severity: "info"},
+				{EventTypeID: "VmBeingHotMigratedEvent", Severity: "warn"},
+			},
+		},
+	}
+
+	c := NewChainCorrelator(time.Minute, cfg.GetSeverity)
+	events := testChainEvents()
+
+	c.Enrich(&events)
+
+	chains := c.Close(time.Now().Add(time.Minute))
+	if len(chains) != 1 {
+		t.Fatalf("Invalid chains: %v", chains)
+	}
+
+	if chains[0].Outcome != chainOutcomeWarning || chains[0].Severity != severityWarning {
+		t.Errorf("Invalid outcome: %v", chains[0].Outcome)
+	}
+}
+
+func TestChainCorrelator_Close_NotIdle(t *testing.T) {
+	c := NewChainCorrelator(time.Minute, config.DefaultSeverityConfig().GetSeverity)
+	events := testChainEvents()
+
+	c.Enrich(&events)
+
+	chains := c.Close(time.Now())
+	if len(chains) != 0 {
+		t.Errorf("Invalid chains: %v", chains)
+	}
+}
+
+func TestChainCorrelator_Close_TaskCompleted(t *testing.T) {
+	c := NewChainCorrelator(time.Minute, config.DefaultSeverityConfig().GetSeverity)
+	events := testChainEvents()
+
+	c.Enrich(&events)
+
+	now := time.Now()
+	c.CompleteTasks([]Task{{Key: "task-1"}}, now)
+
+	chains := c.Close(now)
+	if len(chains) != 0 {
+		t.Errorf("Closed before following events: %v", chains)
+	}
+
+	chains = c.Close(now.Add(ChainCloseDelay))
+	if len(chains) != 1 || chains[0].ChainID != 10 || chains[0].EventCount != 3 {
+		t.Errorf("Invalid chains: %v", chains)
+	}
+}
+
+func TestChainCorrelator_Close_TaskNotCompleted(t *testing.T) {
+	c := NewChainCorrelator(time.Minute, config.DefaultSeverityConfig().GetSeverity)
+	events := testChainEvents()
+
+	c.Enrich(&events)
+
+	now := time.Now()
+	c.CompleteTasks([]Task{{Key: "task-2"}}, now)
+
+	chains := c.Close(now.Add(ChainCloseDelay))
+	if len(chains) != 0 {
+		t.Errorf("Invalid chains: %v", chains)
+	}
+}
+
+func TestChainCorrelator_Enrich_Again(t *testing.T) {
+	c := NewChainCorrelator(time.Minute, config.DefaultSeverityConfig().GetSeverity)
+	events := testChainEvents()
+
+	c.Enrich(&events)
+
+	again := testChainEvents()
+	c.Enrich(&again)
+
+	chains := c.Close(time.Now().Add(time.Minute))
+	if len(chains) != 1 || chains[0].EventCount != 3 {
+		t.Errorf("Invalid chains: %v", chains)
+	}
+}
+
+func TestChainCorrelator_Enrich_AgainAfterClose(t *testing.T) {
+	c := NewChainCorrelator(time.Minute, config.DefaultSeverityConfig().GetSeverity)
+	events := testChainEvents()
+
+	c.Enrich(&events)
+
+	now := time.Now().Add(time.Minute)
+	chains := c.Close(now)
+	if len(chains) != 1 {
+		t.Fatalf("Invalid chains: %v", chains)
+	}
+
+	again := testChainEvents()
+	c.Enrich(&again)
+
+	if again[1].TaskKey == nil || *again[1].TaskKey != "task-1" {
+		t.Errorf("Invalid task key: %v", again[1].TaskKey)
+	}
+
+	chains = c.Close(now.Add(time.Minute))
+	if len(chains) != 0 {
+		t.Errorf("Closed again: %v", chains)
+	}
+}
+
+func TestChainCorrelator_Enrich_Nil(_ *testing.T) {
+	var c *ChainCorrelator
+	events := testChainEvents()
+
+	c.Enrich(&events)
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,117 @@
+package vmomi
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/vmware/govmomi/event"
+	"github.com/vmware/govmomi/vim25"
+)
+
+type Checkpoint struct {
+	Key         int32     `json:"key"`
+	CreatedTime time.Time `json:"created_time"`
+}
+
+func sendAfterCheckpoint(
+	ctx context.Context,
+	c *vim25.Client,
+	em *event.Manager,
+	catalog *Catalog,
+	previous *Checkpoint,
+	events *[]Event,
+	ch chan<- *[]Event,
+) error {
+	if previous == nil || previous.Key == int32(Empty) {
+		return nil
+	}
+
+	if len(*events) == Empty {
+		return nil
+	}
+
+	targets, found := resumeFromKey(ctx, previous, events)
+	if !found {
+		err := backfillEvents(ctx, c, em, catalog, previous, events, ch)
+		if err != nil {
+			return err
+		}
+
+		targets = filterAfterTime(previous, events)
+	}
+
+	if len(*targets) != Empty {
+		ch <- targets
+	}
+
+	return nil
+}
+
+func resumeFromKey(
+	ctx context.Context,
+	previous *Checkpoint,
+	events *[]Event,
+) (*[]Event, bool) {
+	for _, e := range *events {
+		if e.Key != previous.Key {
+			continue
+		}
+
+		if previous.CreatedTime.IsZero() || e.CreatedTime.Equal(previous.CreatedTime) {
+			return filterAfterKey(previous.Key, events), true
+		}
+
+		// Same key but another event, vCenter is restored or key sequence is reset.
+		slog.WarnContext(
+			ctx,
+			"Checkpoint is stale, resume from created time",
+			"key", previous.Key,
+			"checkpoint_time", previous.CreatedTime,
+			"event_time", e.CreatedTime,
+		)
+		return nil, false
+	}
+
+	//revive:disable:add-constant
+	latest := (*events)[len(*events)-1]
+	//revive:enable:add-constant
+	if latest.Key < previous.Key {
+		slog.WarnContext(
+			ctx,
+			"Checkpoint is ahead of latest event, resume from created time",
+			"key", previous.Key,
+			"latest_key", latest.Key,
+		)
+	}
+
+	return nil, false
+}
+
+func filterAfterKey(key int32, events *[]Event) *[]Event {
+	found := false
+	targets := make([]Event, Empty, len(*events))
+	for _, e := range *events {
+		if found {
+			targets = append(targets, e)
+		}
+
+		if e.Key == key {
+			found = true
+		}
+	}
+
+	return &targets
+}
+
+func filterAfterTime(previous *Checkpoint, events *[]Event) *[]Event {
+	targets := make([]Event, Empty, len(*events))
+	for _, e := range *events {
+		if e.CreatedTime.After(previous.CreatedTime) ||
+			(e.CreatedTime.Equal(previous.CreatedTime) && e.Key > previous.Key) {
+			targets = append(targets, e)
+		}
+	}
+
+	return &targets
+}
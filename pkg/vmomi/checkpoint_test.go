@@ -0,0 +1,61 @@
+package vmomi
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+//revive:disable:add-constant
+
+func testEvents() *[]Event {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &[]Event{
+		{Key: 10, CreatedTime: base},
+		{Key: 11, CreatedTime: base.Add(time.Second)},
+		{Key: 12, CreatedTime: base.Add(2 * time.Second)},
+	}
+}
+
+func Test_resumeFromKey_FoundKey(t *testing.T) {
+	events := testEvents()
+	previous := Checkpoint{Key: 10, CreatedTime: (*events)[0].CreatedTime}
+
+	targets, found := resumeFromKey(context.Background(), &previous, events)
+
+	if !found || len(*targets) != 2 || (*targets)[0].Key != 11 {
+		t.Errorf("Invalid events: %v", targets)
+	}
+}
+
+func Test_resumeFromKey_StaleKey(t *testing.T) {
+	events := testEvents()
+	previous := Checkpoint{Key: 11, CreatedTime: (*events)[0].CreatedTime}
+
+	_, found := resumeFromKey(context.Background(), &previous, events)
+	if found {
+		t.Errorf("Invalid found: %v", found)
+	}
+
+	targets := filterAfterTime(&previous, events)
+	if len(*targets) != 2 || (*targets)[0].Key != 11 {
+		t.Errorf("Invalid events: %v", targets)
+	}
+}
+
+func Test_resumeFromKey_ResetKey(t *testing.T) {
+	events := testEvents()
+	previous := Checkpoint{Key: 100, CreatedTime: (*events)[1].CreatedTime}
+
+	_, found := resumeFromKey(context.Background(), &previous, events)
+	if found {
+		t.Errorf("Invalid found: %v", found)
+	}
+
+	targets := filterAfterTime(&previous, events)
+	if len(*targets) != 1 || (*targets)[0].Key != 12 {
+		t.Errorf("Invalid events: %v", targets)
+	}
+}
+
+//revive:enable:add-constant
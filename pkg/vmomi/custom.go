@@ -0,0 +1,258 @@
+package vmomi
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	sx "github.com/9506hqwy/vmomi-event-source/pkg/vmomi/sessionex"
+)
+
+type CustomFieldCache struct {
+	mu     sync.RWMutex
+	names  []string
+	fields map[int32]string
+	values map[string]map[int32]string
+}
+
+func NewCustomFieldCache(names []string) *CustomFieldCache {
+	return &CustomFieldCache{
+		names:  names,
+		fields: map[int32]string{},
+		values: map[string]map[int32]string{},
+	}
+}
+
+//revive:disable:cognitive-complexity
+
+func (f *CustomFieldCache) Watch(ctx context.Context, maxWaitSeconds *int32) error {
+	c, err := login(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer sx.Logout(ctx, c)
+
+	if c.ServiceContent.CustomFieldsManager == nil {
+		return errors.New("custom fields manager not found")
+	}
+
+	v, err := createInventoryView(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	defer destroyInventoryView(ctx, v)
+
+	waiter, filter, err := createCustomFieldWatcher(ctx, c, v)
+	if err != nil {
+		return err
+	}
+
+	defer destroyPropertyCollector(ctx, waiter)
+	defer destroyPropertyFilter(ctx, filter)
+
+	opt := property.WaitOptions{
+		Options: &types.WaitOptions{
+			MaxWaitSeconds: maxWaitSeconds,
+		},
+	}
+
+	// Keep previous values until all objects are loaded.
+	fields := map[int32]string{}
+	values := map[string]map[int32]string{}
+	loaded := false
+
+	return waiter.WaitForUpdatesEx(ctx, &opt, func(updates []types.ObjectUpdate) bool {
+		if loaded {
+			f.update(updates)
+			return false
+		}
+
+		applyCustomFieldUpdates(fields, values, updates)
+
+		if !opt.Truncated {
+			f.replace(fields, values)
+			loaded = true
+		}
+
+		return false
+	})
+}
+
+//revive:enable:cognitive-complexity
+
+func (f *CustomFieldCache) Enrich(events *[]Event) {
+	if f == nil {
+		return
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for idx := range *events {
+		f.enrichEvent(&(*events)[idx])
+	}
+}
+
+func (f *CustomFieldCache) enrichEvent(e *Event) {
+	id := e.EntityID()
+	if id == nil {
+		return
+	}
+
+	values, ok := f.values[*id]
+	if !ok {
+		return
+	}
+
+	customFields := map[string]string{}
+	for key, value := range values {
+		name, ok := f.fields[key]
+		if !ok || !slices.Contains(f.names, name) {
+			continue
+		}
+
+		customFields[name] = value
+	}
+
+	if len(customFields) != Empty {
+		e.CustomFields = customFields
+	}
+}
+
+func (f *CustomFieldCache) update(updates []types.ObjectUpdate) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	applyCustomFieldUpdates(f.fields, f.values, updates)
+}
+
+func (f *CustomFieldCache) replace(fields map[int32]string, values map[string]map[int32]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.fields = fields
+	f.values = values
+}
+
+//revive:disable:cognitive-complexity
+
+func applyCustomFieldUpdates(
+	fields map[int32]string,
+	values map[string]map[int32]string,
+	updates []types.ObjectUpdate,
+) {
+	for _, update := range updates {
+		id := update.Obj.Value
+
+		if update.Kind == types.ObjectUpdateKindLeave {
+			delete(values, id)
+			continue
+		}
+
+		for _, change := range update.ChangeSet {
+			switch change.Name {
+			case "field":
+				replaceCustomFieldDefs(fields, change.Val)
+			case "customValue":
+				values[id] = toCustomFieldValues(change.Val)
+			default:
+				// Not used properties.
+			}
+		}
+	}
+}
+
+//revive:enable:cognitive-complexity
+
+func replaceCustomFieldDefs(fields map[int32]string, value types.AnyType) {
+	clear(fields)
+
+	defs, ok := value.(types.ArrayOfCustomFieldDef)
+	if !ok {
+		// Property value is unset when no field is defined.
+		return
+	}
+
+	for _, def := range defs.CustomFieldDef {
+		fields[def.Key] = def.Name
+	}
+}
+
+func toCustomFieldValues(value types.AnyType) map[int32]string {
+	values := map[int32]string{}
+
+	array, ok := value.(types.ArrayOfCustomFieldValue)
+	if !ok {
+		return values
+	}
+
+	for _, v := range array.CustomFieldValue {
+		if s, ok := v.(*types.CustomFieldStringValue); ok {
+			values[s.Key] = s.Value
+		}
+	}
+
+	return values
+}
+
+func createCustomFieldWatcher(
+	ctx context.Context,
+	c *vim25.Client,
+	v *view.ContainerView,
+) (*property.Collector, *property.Filter, error) {
+	pm := property.DefaultCollector(c)
+
+	waiter, err := pm.Create(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spec := types.PropertyFilterSpec{
+		ObjectSet: []types.ObjectSpec{
+			{
+				Obj: *c.ServiceContent.CustomFieldsManager,
+			},
+			{
+				Obj:  v.Reference(),
+				Skip: types.NewBool(true),
+				SelectSet: []types.BaseSelectionSpec{
+					&types.TraversalSpec{
+						Type: "ContainerView",
+						Path: "view",
+					},
+				},
+			},
+		},
+		PropSet: []types.PropertySpec{
+			{
+				Type:    "CustomFieldsManager",
+				PathSet: []string{"field"},
+			},
+			{
+				Type:    "ManagedEntity",
+				PathSet: []string{"customValue"},
+			},
+		},
+	}
+
+	// Report entire values instead of nested changes of array.
+	req := types.CreateFilter{
+		Spec:           spec,
+		PartialUpdates: false,
+	}
+
+	filter, err := waiter.CreateFilter(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return waiter, filter, nil
+}
@@ -0,0 +1,168 @@
+package vmomi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+//revive:disable:add-constant
+
+func testCustomFieldCache() *CustomFieldCache {
+	f := NewCustomFieldCache([]string{"owner"})
+	f.update([]types.ObjectUpdate{
+		testObjectUpdate(
+			testRef("CustomFieldsManager", "CustomFieldsManager"),
+			types.PropertyChange{
+				Name: "field",
+				Val: types.ArrayOfCustomFieldDef{
+					CustomFieldDef: []types.CustomFieldDef{
+						{Key: 101, Name: "owner"},
+						{Key: 102, Name: "backup"},
+					},
+				},
+			},
+		),
+		testObjectUpdate(
+			testRef("VirtualMachine", "vm-1"),
+			types.PropertyChange{
+				Name: "customValue",
+				Val: types.ArrayOfCustomFieldValue{
+					CustomFieldValue: []types.BaseCustomFieldValue{
+						&types.CustomFieldStringValue{
+							CustomFieldValue: types.CustomFieldValue{Key: 101},
+							Value:            "alice",
+						},
+						&types.CustomFieldStringValue{
+							CustomFieldValue: types.CustomFieldValue{Key: 102},
+							Value:            "daily",
+						},
+					},
+				},
+			},
+		),
+	})
+
+	return f
+}
+
+func TestCustomFieldCache_Enrich_VM(t *testing.T) {
+	f := testCustomFieldCache()
+	events := []Event{{VM: testString("vm01"), VMID: testString("vm-1")}}
+
+	f.Enrich(&events)
+
+	fields := events[0].CustomFields
+	if len(fields) != 1 || fields["owner"] != "alice" {
+		t.Errorf("Invalid custom fields: %v", fields)
+	}
+}
+
+func TestCustomFieldCache_Enrich_Renamed(t *testing.T) {
+	f := testCustomFieldCache()
+	f.update([]types.ObjectUpdate{
+		{
+			Kind: types.ObjectUpdateKindModify,
+			Obj:  testRef("CustomFieldsManager", "CustomFieldsManager"),
+			ChangeSet: []types.PropertyChange{
+				{
+					Name: "field",
+					Val: types.ArrayOfCustomFieldDef{
+						CustomFieldDef: []types.CustomFieldDef{
+							{Key: 101, Name: "manager"},
+							{Key: 102, Name: "owner"},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	events := []Event{{VM: testString("vm01"), VMID: testString("vm-1")}}
+
+	f.Enrich(&events)
+
+	fields := events[0].CustomFields
+	if len(fields) != 1 || fields["owner"] != "daily" {
+		t.Errorf("Invalid custom fields: %v", fields)
+	}
+}
+
+func TestCustomFieldCache_Enrich_Removed(t *testing.T) {
+	f := testCustomFieldCache()
+	f.update([]types.ObjectUpdate{
+		{
+			Kind: types.ObjectUpdateKindLeave,
+			Obj:  testRef("VirtualMachine", "vm-1"),
+		},
+	})
+
+	events := []Event{{VM: testString("vm01"), VMID: testString("vm-1")}}
+
+	f.Enrich(&events)
+
+	if events[0].CustomFields != nil {
+		t.Errorf("Invalid custom fields: %v", events[0].CustomFields)
+	}
+}
+
+func TestCustomFieldCache_Enrich_Nil(_ *testing.T) {
+	var f *CustomFieldCache
+	events := []Event{{VM: testString("vm01"), VMID: testString("vm-1")}}
+
+	f.Enrich(&events)
+}
+
+func testSetCustomField(
+	ctx context.Context,
+	t *testing.T,
+	c *vim25.Client,
+	name string,
+	value string,
+) string {
+	m, err := object.GetCustomFieldsManager(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	def, err := m.Add(ctx, name, "VirtualMachine", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm := simulator.Map(ctx).Any("VirtualMachine").Reference()
+	err = m.Set(ctx, vm, def.Key, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return vm.Value
+}
+
+func TestCustomFieldCache_Watch(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm := testSetCustomField(ctx, t, c, "owner", "alice")
+		testSetCustomField(ctx, t, c, "backup", "daily")
+
+		f := NewCustomFieldCache([]string{"owner"})
+		maxWaitSeconds := int32(1)
+		err := f.Watch(testTargetContext(ctx, c), &maxWaitSeconds)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		events := []Event{{VM: testString("vm"), VMID: &vm}}
+		f.Enrich(&events)
+
+		fields := events[0].CustomFields
+		if len(fields) != 1 || fields["owner"] != "alice" {
+			t.Errorf("Invalid custom fields: %v", fields)
+		}
+	})
+}
+
+//revive:enable:add-constant
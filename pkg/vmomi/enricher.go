@@ -0,0 +1,5 @@
+package vmomi
+
+type Enricher interface {
+	Enrich(events *[]Event)
+}
@@ -25,20 +25,46 @@ const (
 
 const Empty = int(0)
 
+const (
+	severityError   = "error"
+	severityInfo    = "info"
+	severityWarning = "warning"
+)
+
 type Event struct {
-	Key                      int32
-	ComputeResource          *string
-	CreatedTime              time.Time
-	Datacenter               *string
-	Datastore                *string
-	DistributedVirtualSwitch *string
-	FullFormattedMessage     string
-	Host                     *string
-	Network                  *string
-	UserName                 string
-	VM                       *string
-	Severity                 string
-	EventTypeID              string
+	Key                        int32
+	ComputeResource            *string
+	ComputeResourceID          *string
+	CreatedTime                time.Time
+	Datacenter                 *string
+	DatacenterID               *string
+	Datastore                  *string
+	DatastoreID                *string
+	DistributedVirtualSwitch   *string
+	DistributedVirtualSwitchID *string
+	FullFormattedMessage       string
+	Host                       *string
+	HostID                     *string
+	Network                    *string
+	NetworkID                  *string
+	UserName                   string
+	VM                         *string
+	VMID                       *string
+	Severity                   string
+	EventTypeID                string
+	Attributes                 map[string]string
+	InventoryPath              *string
+	Folder                     *string
+	ResourcePool               *string
+	Tags                       map[string][]string
+	CustomFields               map[string]string
+	ChainID                    int32
+	ChangeTag                  *string
+	TaskKey                    *string
+	Messages                   map[string]string
+	Message                    string
+	// Arguments are walked on demand because walking large values is slow.
+	source types.BaseEvent
 }
 
 type EventInfo struct {
@@ -64,6 +90,7 @@ type EventCause struct {
 }
 
 //revive:disable:cognitive-complexity
+//revive:disable:cyclomatic
 
 func (e Event) Target() string {
 	path := []string{}
@@ -80,7 +107,7 @@ func (e Event) Target() string {
 		path = append(path, *e.ComputeResource)
 	}
 
-	if e.Host != nil && *e.ComputeResource != *e.Host {
+	if e.Host != nil && !e.IsStandaloneHost() {
 		path = append(path, *e.Host)
 	}
 
@@ -96,9 +123,51 @@ func (e Event) Target() string {
 		path = append(path, *e.VM)
 	}
 
-	return strings.Join(path, "/")
+	target := strings.Join(path, "/")
+
+	// Disambiguate by ID because name is not unique.
+	id := e.EntityID()
+	if id != nil && *id != "" {
+		target = fmt.Sprintf("%s (%s)", target, *id)
+	}
+
+	return target
 }
 
+func (e Event) EntityID() *string {
+	switch {
+	case e.VM != nil:
+		return e.VMID
+	case e.Datastore != nil:
+		return e.DatastoreID
+	case e.Network != nil:
+		return e.NetworkID
+	case e.Host != nil && !e.IsStandaloneHost():
+		return e.HostID
+	case e.ComputeResource != nil:
+		return e.ComputeResourceID
+	case e.DistributedVirtualSwitch != nil:
+		return e.DistributedVirtualSwitchID
+	case e.Datacenter != nil:
+		return e.DatacenterID
+	default:
+		return nil
+	}
+}
+
+func (e Event) IsStandaloneHost() bool {
+	return e.ComputeResource != nil && e.Host != nil && *e.ComputeResource == *e.Host
+}
+
+func (e *Event) GetAttributes() map[string]string {
+	if e.Attributes == nil && e.source != nil {
+		e.Attributes = getEventAttributes(e.source)
+	}
+
+	return e.Attributes
+}
+
+//revive:enable:cyclomatic
 //revive:enable:cognitive-complexity
 
 func Query(ctx context.Context) ([]Event, error) {
@@ -109,12 +178,7 @@ func Query(ctx context.Context) ([]Event, error) {
 
 	defer sx.Logout(ctx, c)
 
-	locale, err := sx.GetLocale(ctx, c)
-	if err != nil {
-		return nil, err
-	}
-
-	err = cacheLocalizationCatalogAll(ctx, c, *locale)
+	catalog, err := loadCatalog(ctx, c)
 	if err != nil {
 		return nil, err
 	}
@@ -143,16 +207,17 @@ func Query(ctx context.Context) ([]Event, error) {
 		return nil, err
 	}
 
-	return ToEvents(e, &events), nil
+	return ToEvents(e, catalog, &events), nil
 }
 
 //revive:disable:cognitive-complexity
 
-func Poll(
+func QueryRange(
 	ctx context.Context,
-	maxWaitSeconds *int32,
+	begin *time.Time,
+	end *time.Time,
+	maxCount int,
 	ch chan<- *[]Event,
-	previousKey int32,
 ) error {
 	c, err := login(ctx)
 	if err != nil {
@@ -162,13 +227,70 @@ func Poll(
 
 	defer sx.Logout(ctx, c)
 
-	locale, err := sx.GetLocale(ctx, c)
+	catalog, err := loadCatalog(ctx, c)
+	if err != nil {
+		close(ch)
+		return err
+	}
+
+	em := event.NewManager(c)
+
+	collector, _, err := createEventCollectorByTime(ctx, em, begin, end)
+	if err != nil {
+		close(ch)
+		return err
+	}
+
+	defer destroyEventCollector(ctx, collector)
+
+	e, err := getEventManager(ctx, c)
+	if err != nil {
+		close(ch)
+		return err
+	}
+
+	count := Empty
+	err = readEventPages(ctx, e, catalog, collector, func(events *[]Event) bool {
+		if maxCount > Empty && count+len(*events) >= maxCount {
+			page := (*events)[:maxCount-count]
+			ch <- &page
+			return false
+		}
+
+		count += len(*events)
+		ch <- events
+		return true
+	})
+	if err != nil {
+		close(ch)
+		return err
+	}
+
+	close(ch)
+	return nil
+}
+
+//revive:enable:cognitive-complexity
+
+//revive:disable:cognitive-complexity
+
+func Poll(
+	ctx context.Context,
+	maxWaitSeconds *int32,
+	ch chan<- *[]Event,
+	previous *Checkpoint,
+) error {
+	c, err := login(ctx)
 	if err != nil {
+		close(ch)
 		return err
 	}
 
-	err = cacheLocalizationCatalogAll(ctx, c, *locale)
+	defer sx.Logout(ctx, c)
+
+	catalog, err := loadCatalog(ctx, c)
 	if err != nil {
+		close(ch)
 		return err
 	}
 
@@ -182,13 +304,13 @@ func Poll(
 
 	defer destroyEventCollector(ctx, collector)
 
-	events, err := readyEventCollector(ctx, c, collector)
+	events, err := readyEventCollector(ctx, c, catalog, collector)
 	if err != nil {
 		close(ch)
 		return err
 	}
 
-	err = sendAfterKey(previousKey, events, ch)
+	err = sendAfterCheckpoint(ctx, c, em, catalog, previous, events, ch)
 	if err != nil {
 		close(ch)
 		return err
@@ -209,6 +331,7 @@ func Poll(
 		waiter,
 		maxWaitSeconds,
 		collector,
+		catalog,
 		func(evts *[]Event) {
 			if len(*evts) != Empty {
 				ch <- evts
@@ -315,10 +438,10 @@ func GetEventInfo(ctx context.Context) ([]EventInfo, error) {
 	return info, nil
 }
 
-func ToEvents(em *mo.EventManager, events *[]types.BaseEvent) []Event {
+func ToEvents(em *mo.EventManager, catalog *Catalog, events *[]types.BaseEvent) []Event {
 	metrics := make([]Event, len(*events))
 	for i, e := range *events {
-		metrics[i] = ToEvent(em, e)
+		metrics[i] = ToEvent(em, catalog, e)
 	}
 
 	sort.Slice(
@@ -331,48 +454,78 @@ func ToEvents(em *mo.EventManager, events *[]types.BaseEvent) []Event {
 	return metrics
 }
 
-func ToEvent(em *mo.EventManager, e types.BaseEvent) Event {
+func ToEvent(em *mo.EventManager, catalog *Catalog, e types.BaseEvent) Event {
 	evt := *e.GetEvent()
 	model := Event{
 		Key:                  evt.Key,
 		CreatedTime:          evt.CreatedTime,
 		FullFormattedMessage: evt.FullFormattedMessage,
 		UserName:             evt.UserName,
-		Severity:             getEventSeverity(em, &e),
+		Severity:             getEventSeverity(em, catalog, &e),
 		EventTypeID:          getEventTypeID(&e),
+		source:               e,
+		ChainID:              evt.ChainId,
+		ChangeTag:            getEventChangeTag(&evt),
+		TaskKey:              getEventTaskKey(e),
 	}
 
 	if evt.ComputeResource != nil {
 		model.ComputeResource = &evt.ComputeResource.Name
+		model.ComputeResourceID = &evt.ComputeResource.ComputeResource.Value
 	}
 
 	if evt.Datacenter != nil {
 		model.Datacenter = &evt.Datacenter.Name
+		model.DatacenterID = &evt.Datacenter.Datacenter.Value
 	}
 
 	if evt.Ds != nil {
 		model.Datastore = &evt.Ds.Name
+		model.DatastoreID = &evt.Ds.Datastore.Value
 	}
 
 	if evt.Dvs != nil {
 		model.DistributedVirtualSwitch = &evt.Dvs.Name
+		model.DistributedVirtualSwitchID = &evt.Dvs.Dvs.Value
 	}
 
 	if evt.Host != nil {
 		model.Host = &evt.Host.Name
+		model.HostID = &evt.Host.Host.Value
 	}
 
 	if evt.Net != nil {
 		model.Network = &evt.Net.Name
+		model.NetworkID = &evt.Net.Network.Value
 	}
 
 	if evt.Vm != nil {
 		model.VM = &evt.Vm.Name
+		model.VMID = &evt.Vm.Vm.Value
 	}
 
+	model.Message = getEventMessage(em, catalog, &model)
+
 	return model
 }
 
+func getEventChangeTag(evt *types.Event) *string {
+	if len(evt.ChangeTag) == Empty {
+		return nil
+	}
+
+	return &evt.ChangeTag
+}
+
+func getEventTaskKey(e types.BaseEvent) *string {
+	taskEvent, ok := e.(*types.TaskEvent)
+	if !ok {
+		return nil
+	}
+
+	return &taskEvent.Info.Key
+}
+
 func getEventManager(ctx context.Context, c *vim25.Client) (*mo.EventManager, error) {
 	pc := property.DefaultCollector(c)
 
@@ -395,10 +548,10 @@ func getEventManager(ctx context.Context, c *vim25.Client) (*mo.EventManager, er
 	return &e, nil
 }
 
-func getEventSeverity(em *mo.EventManager, evt *types.BaseEvent) string {
+func getEventSeverity(em *mo.EventManager, catalog *Catalog, evt *types.BaseEvent) string {
 	typeID := getEventTypeID(evt)
 
-	severity := getCatalogCategory(typeID)
+	severity := getCatalogCategory(catalog, typeID)
 	if severity != nil && containsCategoryKey(em, *severity) {
 		return *severity
 	}
@@ -420,7 +573,7 @@ func getCategoryKey(em *mo.EventManager, typeID string) string {
 	}
 
 	// Default severity
-	return "info"
+	return severityInfo
 }
 
 func containsCategoryKey(em *mo.EventManager, key string) bool {
@@ -474,8 +627,43 @@ func createEventCollector(
 	ctx context.Context,
 	em *event.Manager,
 ) (*event.HistoryCollector, error) {
-	filter := types.EventFilterSpec{}
+	filter, err := getEventFilterSpec(ctx, em.Client())
+	if err != nil {
+		return nil, err
+	}
+
+	return createEventCollectorWithFilter(ctx, em, *filter)
+}
+
+func createEventCollectorByTime(
+	ctx context.Context,
+	em *event.Manager,
+	begin *time.Time,
+	end *time.Time,
+) (*event.HistoryCollector, *types.EventFilterSpec, error) {
+	filter, err := getEventFilterSpec(ctx, em.Client())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter.Time = &types.EventFilterSpecByTime{
+		BeginTime: begin,
+		EndTime:   end,
+	}
 
+	collector, err := createEventCollectorWithFilter(ctx, em, *filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return collector, filter, nil
+}
+
+func createEventCollectorWithFilter(
+	ctx context.Context,
+	em *event.Manager,
+	filter types.EventFilterSpec,
+) (*event.HistoryCollector, error) {
 	collector, err := sx.ExecCallAPI(
 		ctx,
 		func(cctx context.Context) (*event.HistoryCollector, error) {
@@ -514,6 +702,16 @@ func createLatestEventWatcher(
 		},
 	}
 
+	// Watch extensions to load catalog of new extension.
+	ex := em.Client().ServiceContent.ExtensionManager
+	if ex != nil {
+		spec.ObjectSet = append(spec.ObjectSet, types.ObjectSpec{Obj: *ex})
+		spec.PropSet = append(spec.PropSet, types.PropertySpec{
+			Type:    "ExtensionManager",
+			PathSet: []string{"extensionList"},
+		})
+	}
+
 	req := types.CreateFilter{
 		Spec:           spec,
 		PartialUpdates: false,
@@ -530,6 +728,7 @@ func createLatestEventWatcher(
 func readyEventCollector(
 	ctx context.Context,
 	c *vim25.Client,
+	catalog *Catalog,
 	collector *event.HistoryCollector,
 ) (*[]Event, error) {
 	_, err := sx.ExecCallAPI(
@@ -557,13 +756,14 @@ func readyEventCollector(
 		return nil, err
 	}
 
-	es := ToEvents(e, &events)
+	es := ToEvents(e, catalog, &events)
 	return &es, nil
 }
 
 func destroyEventCollector(ctx context.Context, collector *event.HistoryCollector) error {
+	// Destroy even if watching is cancelled.
 	_, err := sx.ExecCallAPI(
-		ctx,
+		context.WithoutCancel(ctx),
 		func(cctx context.Context) (int, error) {
 			return 0, collector.Destroy(cctx)
 		},
@@ -572,8 +772,9 @@ func destroyEventCollector(ctx context.Context, collector *event.HistoryCollecto
 }
 
 func destroyPropertyCollector(ctx context.Context, collector *property.Collector) error {
+	// Destroy even if watching is cancelled.
 	_, err := sx.ExecCallAPI(
-		ctx,
+		context.WithoutCancel(ctx),
 		func(cctx context.Context) (int, error) {
 			return 0, collector.Destroy(cctx)
 		},
@@ -582,8 +783,9 @@ func destroyPropertyCollector(ctx context.Context, collector *property.Collector
 }
 
 func destroyPropertyFilter(ctx context.Context, filter *property.Filter) error {
+	// Destroy even if watching is cancelled.
 	_, err := sx.ExecCallAPI(
-		ctx,
+		context.WithoutCancel(ctx),
 		func(cctx context.Context) (int, error) {
 			return 0, filter.Destroy(cctx)
 		},
@@ -597,6 +799,7 @@ func waitUpdateForLatestEvent(
 	waiter *property.Collector,
 	maxWaitSeconds *int32,
 	collector *event.HistoryCollector,
+	catalog *Catalog,
 	onUpdatesFn func(*[]Event),
 ) error {
 	opt := property.WaitOptions{
@@ -611,7 +814,16 @@ func waitUpdateForLatestEvent(
 		return err
 	}
 
-	err = waiter.WaitForUpdatesEx(ctx, &opt, func(_ []types.ObjectUpdate) bool {
+	watcher := catalogWatcher{
+		catalog: catalog,
+		ttl:     getCatalogTTL(ctx),
+	}
+
+	defer watcher.Wait()
+
+	err = waiter.WaitForUpdatesEx(ctx, &opt, func(updates []types.ObjectUpdate) bool {
+		catalog := watcher.Update(ctx, c, updates)
+
 		evts, err := sx.ExecCallAPI(
 			ctx,
 			func(cctx context.Context) ([]types.BaseEvent, error) {
@@ -623,7 +835,7 @@ func waitUpdateForLatestEvent(
 			return false
 		}
 
-		es := ToEvents(e, &evts)
+		es := ToEvents(e, catalog, &evts)
 		onUpdatesFn(&es)
 
 		return false
@@ -634,33 +846,3 @@ func waitUpdateForLatestEvent(
 
 	return nil
 }
-
-func sendAfterKey(key int32, events *[]Event, ch chan<- *[]Event) error {
-	if key == int32(Empty) {
-		return nil
-	}
-
-	if len(*events) == Empty {
-		return nil
-	}
-
-	ch <- filterAfterKey(key, events)
-
-	return nil
-}
-
-func filterAfterKey(key int32, events *[]Event) *[]Event {
-	found := false
-	targets := make([]Event, Empty, len(*events))
-	for _, e := range *events {
-		if found {
-			targets = append(targets, e)
-		}
-
-		if e.Key == key {
-			found = true
-		}
-	}
-
-	return &targets
-}
@@ -0,0 +1,171 @@
+package vmomi
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/event"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+)
+
+//revive:disable:add-constant
+
+func testString(s string) *string {
+	return &s
+}
+
+func TestEvent_Target_VM(t *testing.T) {
+	e := Event{
+		Datacenter:        testString("DC0"),
+		DatacenterID:      testString("datacenter-1"),
+		ComputeResource:   testString("Cluster0"),
+		ComputeResourceID: testString("domain-c1"),
+		Host:              testString("esxi01"),
+		HostID:            testString("host-1"),
+		VM:                testString("vm01"),
+		VMID:              testString("vm-1"),
+	}
+
+	target := e.Target()
+	if target != "DC0/Cluster0/esxi01/vm01 (vm-1)" {
+		t.Errorf("Invalid target: %v", target)
+	}
+}
+
+func TestEvent_Target_StandaloneHost(t *testing.T) {
+	e := Event{
+		Datacenter:        testString("DC0"),
+		DatacenterID:      testString("datacenter-1"),
+		ComputeResource:   testString("esxi01"),
+		ComputeResourceID: testString("domain-s1"),
+		Host:              testString("esxi01"),
+		HostID:            testString("host-1"),
+	}
+
+	target := e.Target()
+	if target != "DC0/esxi01 (domain-s1)" {
+		t.Errorf("Invalid target: %v", target)
+	}
+}
+
+func TestEvent_Target_HostOnly(t *testing.T) {
+	e := Event{
+		Host:   testString("esxi01"),
+		HostID: testString("host-1"),
+	}
+
+	target := e.Target()
+	if target != "esxi01 (host-1)" {
+		t.Errorf("Invalid target: %v", target)
+	}
+}
+
+func testQueryRange(
+	ctx context.Context,
+	t *testing.T,
+	begin *time.Time,
+	maxCount int,
+) []Event {
+	t.Helper()
+
+	ch := make(chan *[]Event)
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- QueryRange(ctx, begin, nil, maxCount, ch)
+	}()
+
+	events := []Event{}
+	for page := range ch {
+		events = append(events, *page...)
+	}
+
+	err := <-errCh
+	if err != nil {
+		t.Fatalf("QueryRange error: %v", err)
+	}
+
+	return events
+}
+
+func TestQueryRange_Begin(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		testPowerOff(ctx, t, c, "DC0_H0_VM0")
+
+		begin := time.Now()
+		time.Sleep(10 * time.Millisecond)
+
+		testPowerOff(ctx, t, c, "DC0_H0_VM1")
+
+		events := testQueryRange(testTargetContext(ctx, c), t, &begin, 0)
+		if len(events) == 0 {
+			t.Fatal("No events in range")
+		}
+
+		outOfRange := slices.ContainsFunc(events, func(e Event) bool {
+			return e.CreatedTime.Before(begin) || (e.VM != nil && *e.VM == "DC0_H0_VM0")
+		})
+		if outOfRange {
+			t.Errorf("Event out of range: %v", events)
+		}
+	})
+}
+
+func TestQueryRange_Max(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		testPowerOff(ctx, t, c, "DC0_H0_VM0")
+		testPowerOff(ctx, t, c, "DC0_H0_VM1")
+
+		events := testQueryRange(testTargetContext(ctx, c), t, nil, 3)
+		if len(events) != 3 {
+			t.Errorf("Invalid count: %d", len(events))
+		}
+
+		all := testQueryRange(testTargetContext(ctx, c), t, nil, 0)
+		if len(all) <= 3 {
+			t.Errorf("Invalid count: %d", len(all))
+		}
+	})
+}
+
+func TestQueryRange_Error(t *testing.T) {
+	ctx := context.WithValue(t.Context(), flag.TargetURLKey{}, "http://127.0.0.1:1/sdk")
+	ctx = context.WithValue(ctx, flag.TargetUserKey{}, "user")
+	ctx = context.WithValue(ctx, flag.TargetPasswordKey{}, "pass")
+	ctx = context.WithValue(ctx, flag.TargetNoVerifySSLKey{}, true)
+
+	ch := make(chan *[]Event)
+
+	err := QueryRange(ctx, nil, nil, 0, ch)
+	if err == nil {
+		t.Error("QueryRange succeeded for unreachable server")
+	}
+
+	if _, ok := <-ch; ok {
+		t.Error("Channel is not closed")
+	}
+}
+
+func Test_destroyEventCollector_Cancelled(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		collector, err := createEventCollector(ctx, event.NewManager(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err = destroyEventCollector(cctx, collector)
+		if err != nil {
+			t.Errorf("Not destroyed: %v", err)
+		}
+	})
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,87 @@
+package vmomi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+	sx "github.com/9506hqwy/vmomi-event-source/pkg/vmomi/sessionex"
+)
+
+func getEventFilterSpec(ctx context.Context, c *vim25.Client) (*types.EventFilterSpec, error) {
+	spec := types.EventFilterSpec{}
+
+	f, ok := ctx.Value(flag.TargetFilterKey{}).(*config.Filter)
+	if !ok || f == nil {
+		return &spec, nil
+	}
+
+	if f.Entity != nil {
+		entity, err := createEntityFilterSpec(ctx, c, f.Entity)
+		if err != nil {
+			return nil, err
+		}
+
+		spec.Entity = entity
+	}
+
+	spec.EventTypeId = f.EventTypeIDs
+	spec.Category = f.Categories
+
+	if len(f.UserNames) != Empty || f.SystemUser {
+		spec.UserName = &types.EventFilterSpecByUsername{
+			SystemUser: f.SystemUser,
+			UserList:   f.UserNames,
+		}
+	}
+
+	return &spec, nil
+}
+
+func createEntityFilterSpec(
+	ctx context.Context,
+	c *vim25.Client,
+	f *config.EntityFilter,
+) (*types.EventFilterSpecByEntity, error) {
+	recursion := types.EventFilterSpecRecursionOptionAll
+	if f.Recursion != "" {
+		recursion = types.EventFilterSpecRecursionOption(f.Recursion)
+	}
+
+	if !slices.Contains(recursion.Values(), recursion) {
+		return nil, fmt.Errorf("invalid recursion: %s", f.Recursion)
+	}
+
+	si := object.NewSearchIndex(c)
+	entity, err := sx.ExecCallAPI(
+		ctx,
+		func(cctx context.Context) (object.Reference, error) {
+			return si.FindByInventoryPath(cctx, f.Path)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if entity == nil {
+		return nil, fmt.Errorf("entity not found: %s", f.Path)
+	}
+
+	return &types.EventFilterSpecByEntity{
+		Entity:    entity.Reference(),
+		Recursion: recursion,
+	}, nil
+}
+
+func isFilteredEvent(spec *types.EventFilterSpec) bool {
+	return spec.Entity != nil ||
+		len(spec.EventTypeId) != Empty ||
+		len(spec.Category) != Empty ||
+		spec.UserName != nil
+}
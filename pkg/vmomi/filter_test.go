@@ -0,0 +1,202 @@
+package vmomi
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/vmware/govmomi/event"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+)
+
+//revive:disable:add-constant
+
+func Test_getEventFilterSpec(t *testing.T) {
+	cases := map[string]struct {
+		filter   *config.Filter
+		expected types.EventFilterSpec
+	}{
+		"empty": {
+			filter:   &config.Filter{},
+			expected: types.EventFilterSpec{},
+		},
+		"event_type_ids": {
+			filter:   &config.Filter{EventTypeIDs: []string{"VmPoweredOffEvent"}},
+			expected: types.EventFilterSpec{EventTypeId: []string{"VmPoweredOffEvent"}},
+		},
+		"categories": {
+			filter:   &config.Filter{Categories: []string{"warning", "error"}},
+			expected: types.EventFilterSpec{Category: []string{"warning", "error"}},
+		},
+		"user_names": {
+			filter: &config.Filter{UserNames: []string{"root"}},
+			expected: types.EventFilterSpec{
+				UserName: &types.EventFilterSpecByUsername{UserList: []string{"root"}},
+			},
+		},
+		"system_user": {
+			filter: &config.Filter{SystemUser: true},
+			expected: types.EventFilterSpec{
+				UserName: &types.EventFilterSpecByUsername{SystemUser: true},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		ctx := context.WithValue(t.Context(), flag.TargetFilterKey{}, c.filter)
+
+		spec, err := getEventFilterSpec(ctx, nil)
+		if err != nil {
+			t.Fatalf("%s: getEventFilterSpec error: %v", name, err)
+		}
+
+		if !reflect.DeepEqual(*spec, c.expected) {
+			t.Errorf("%s: Invalid spec: %v", name, spec)
+		}
+
+		if isFilteredEvent(spec) != (name != "empty") {
+			t.Errorf("%s: Invalid filtered: %v", name, spec)
+		}
+	}
+}
+
+func testFilteredEvents(
+	ctx context.Context,
+	t *testing.T,
+	c *vim25.Client,
+	f *config.Filter,
+) []string {
+	t.Helper()
+
+	ctx = context.WithValue(ctx, flag.TargetFilterKey{}, f)
+
+	spec, err := getEventFilterSpec(ctx, c)
+	if err != nil {
+		t.Fatalf("getEventFilterSpec error: %v", err)
+	}
+
+	collector, err := event.NewManager(c).CreateCollectorForEvents(ctx, *spec)
+	if err != nil {
+		t.Fatalf("CreateCollectorForEvents error: %v", err)
+	}
+
+	events, err := collector.LatestPage(ctx)
+	if err != nil {
+		t.Fatalf("LatestPage error: %v", err)
+	}
+
+	matched := []string{}
+	for _, e := range events {
+		if vm := e.GetEvent().Vm; vm != nil {
+			matched = append(matched, reflect.TypeOf(e).Elem().Name()+":"+vm.Name)
+		}
+	}
+
+	return matched
+}
+
+func testPowerOff(ctx context.Context, t *testing.T, c *vim25.Client, name string) {
+	t.Helper()
+
+	vm, err := find.NewFinder(c).VirtualMachine(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := vm.PowerOff(ctx)
+	if err == nil {
+		err = task.Wait(ctx)
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+type testFilterCase struct {
+	filter   *config.Filter
+	included []string
+	excluded []string
+}
+
+func testFilterCases() map[string]testFilterCase {
+	return map[string]testFilterCase{
+		"entity": {
+			filter: &config.Filter{
+				Entity: &config.EntityFilter{Path: "/DC0/vm/DC0_H0_VM0", Recursion: "self"},
+			},
+			included: []string{"VmPoweredOffEvent:DC0_H0_VM0"},
+			excluded: []string{"VmPoweredOffEvent:DC0_H0_VM1"},
+		},
+		"event_type_ids": {
+			filter:   &config.Filter{EventTypeIDs: []string{"VmPoweredOffEvent"}},
+			included: []string{"VmPoweredOffEvent:DC0_H0_VM1"},
+			excluded: []string{"VmStoppingEvent:DC0_H0_VM1"},
+		},
+		"entity and event_type_ids": {
+			filter: &config.Filter{
+				Entity:       &config.EntityFilter{Path: "/DC0/vm/DC0_H0_VM1"},
+				EventTypeIDs: []string{"VmStoppingEvent"},
+			},
+			included: []string{"VmStoppingEvent:DC0_H0_VM1"},
+			excluded: []string{
+				"VmPoweredOffEvent:DC0_H0_VM1",
+				"VmStoppingEvent:DC0_H0_VM0",
+			},
+		},
+	}
+}
+
+func testMatchFilter(t *testing.T, name string, events []string, tc testFilterCase) {
+	t.Helper()
+
+	for _, e := range tc.included {
+		if !slices.Contains(events, e) {
+			t.Errorf("%s: Not included: %s in %v", name, e, events)
+		}
+	}
+
+	for _, e := range tc.excluded {
+		if slices.Contains(events, e) {
+			t.Errorf("%s: Not excluded: %s in %v", name, e, events)
+		}
+	}
+}
+
+func Test_getEventFilterSpec_Match(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		testPowerOff(ctx, t, c, "DC0_H0_VM0")
+		testPowerOff(ctx, t, c, "DC0_H0_VM1")
+
+		for name, tc := range testFilterCases() {
+			testMatchFilter(t, name, testFilteredEvents(ctx, t, c, tc.filter), tc)
+		}
+	})
+}
+
+func Test_getEventFilterSpec_InvalidEntity(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		cases := map[string]*config.EntityFilter{
+			"path":      {Path: "/DC0/vm/notfound"},
+			"recursion": {Path: "/DC0/vm/DC0_H0_VM0", Recursion: "invalid"},
+		}
+
+		for name, entity := range cases {
+			fctx := context.WithValue(ctx, flag.TargetFilterKey{}, &config.Filter{Entity: entity})
+
+			_, err := getEventFilterSpec(fctx, c)
+			if err == nil {
+				t.Errorf("%s: Invalid entity accepted", name)
+			}
+		}
+	})
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,346 @@
+package vmomi
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	sx "github.com/9506hqwy/vmomi-event-source/pkg/vmomi/sessionex"
+)
+
+const MaxInventoryDepth = 256
+
+const inventoryPathSeparator = "/"
+
+const parentVAppProperty = "parentVApp"
+
+type Inventory struct {
+	mu      sync.RWMutex
+	objects map[string]inventoryObject
+}
+
+type inventoryObject struct {
+	Type         string
+	Name         string
+	Parent       *types.ManagedObjectReference
+	ParentFolder *types.ManagedObjectReference
+	ParentVApp   *types.ManagedObjectReference
+	ResourcePool *types.ManagedObjectReference
+}
+
+func NewInventory() *Inventory {
+	return &Inventory{
+		objects: map[string]inventoryObject{},
+	}
+}
+
+//revive:disable:cognitive-complexity
+
+func (i *Inventory) Watch(ctx context.Context, maxWaitSeconds *int32) error {
+	c, err := login(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer sx.Logout(ctx, c)
+
+	v, err := createInventoryView(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	defer destroyInventoryView(ctx, v)
+
+	waiter, filter, err := createInventoryWatcher(ctx, c, v)
+	if err != nil {
+		return err
+	}
+
+	defer destroyPropertyCollector(ctx, waiter)
+	defer destroyPropertyFilter(ctx, filter)
+
+	opt := property.WaitOptions{
+		Options: &types.WaitOptions{
+			MaxWaitSeconds: maxWaitSeconds,
+		},
+	}
+
+	// Keep previous inventory until all objects are loaded.
+	objects := map[string]inventoryObject{}
+	loaded := false
+
+	return waiter.WaitForUpdatesEx(ctx, &opt, func(updates []types.ObjectUpdate) bool {
+		if loaded {
+			i.update(updates)
+			return false
+		}
+
+		applyInventoryUpdates(objects, updates)
+
+		if !opt.Truncated {
+			i.replace(objects)
+			loaded = true
+		}
+
+		return false
+	})
+}
+
+//revive:enable:cognitive-complexity
+
+func (i *Inventory) Enrich(events *[]Event) {
+	if i == nil {
+		return
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	for idx := range *events {
+		i.enrichEvent(&(*events)[idx])
+	}
+}
+
+func (i *Inventory) enrichEvent(e *Event) {
+	id := e.EntityID()
+	if id == nil {
+		return
+	}
+
+	obj, ok := i.objects[*id]
+	if !ok {
+		return
+	}
+
+	e.InventoryPath = i.path(*id, inventoryObject.getFolderParent)
+
+	if folder := i.folder(obj.getFolderParent()); folder != nil {
+		e.Folder = i.path(folder.Value, inventoryObject.getFolderParent)
+	}
+
+	if obj.ResourcePool != nil {
+		e.ResourcePool = i.path(obj.ResourcePool.Value, inventoryObject.getParent)
+	}
+}
+
+func (i *Inventory) path(
+	id string,
+	getParent func(inventoryObject) *types.ManagedObjectReference,
+) *string {
+	names := []string{}
+
+	for range MaxInventoryDepth {
+		obj, ok := i.objects[id]
+		if !ok || getParent(obj) == nil {
+			// Root folder is not included in inventory.
+			break
+		}
+
+		names = append(names, obj.Name)
+		id = getParent(obj).Value
+	}
+
+	if len(names) == Empty {
+		return nil
+	}
+
+	slices.Reverse(names)
+
+	path := inventoryPathSeparator + strings.Join(names, inventoryPathSeparator)
+	return &path
+}
+
+func (i *Inventory) folder(
+	parent *types.ManagedObjectReference,
+) *types.ManagedObjectReference {
+	for range MaxInventoryDepth {
+		if parent == nil {
+			return nil
+		}
+
+		obj, ok := i.objects[parent.Value]
+		if !ok {
+			return nil
+		}
+
+		if obj.Type == "Folder" {
+			return parent
+		}
+
+		parent = obj.getFolderParent()
+	}
+
+	return nil
+}
+
+func (o inventoryObject) getParent() *types.ManagedObjectReference {
+	return o.Parent
+}
+
+// getFolderParent returns vApp or folder instead of resource pool for vApp and VM in vApp.
+func (o inventoryObject) getFolderParent() *types.ManagedObjectReference {
+	switch {
+	case o.ParentVApp != nil:
+		return o.ParentVApp
+	case o.ParentFolder != nil:
+		return o.ParentFolder
+	default:
+		return o.Parent
+	}
+}
+
+func (i *Inventory) update(updates []types.ObjectUpdate) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	applyInventoryUpdates(i.objects, updates)
+}
+
+func (i *Inventory) replace(objects map[string]inventoryObject) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.objects = objects
+}
+
+//revive:disable:cognitive-complexity
+
+func applyInventoryUpdates(objects map[string]inventoryObject, updates []types.ObjectUpdate) {
+	for _, update := range updates {
+		id := update.Obj.Value
+
+		if update.Kind == types.ObjectUpdateKindLeave {
+			delete(objects, id)
+			continue
+		}
+
+		obj := objects[id]
+		obj.Type = update.Obj.Type
+
+		for _, change := range update.ChangeSet {
+			switch change.Name {
+			case "name":
+				obj.Name = toName(change.Val)
+			case "parent":
+				obj.Parent = toReference(change.Val)
+			case "parentFolder":
+				obj.ParentFolder = toReference(change.Val)
+			case parentVAppProperty:
+				obj.ParentVApp = toReference(change.Val)
+			case "resourcePool":
+				obj.ResourcePool = toReference(change.Val)
+			default:
+				// Not used properties.
+			}
+		}
+
+		objects[id] = obj
+	}
+}
+
+//revive:enable:cognitive-complexity
+
+func toName(value types.AnyType) string {
+	name, ok := value.(string)
+	if !ok {
+		return ""
+	}
+
+	return name
+}
+
+func toReference(value types.AnyType) *types.ManagedObjectReference {
+	ref, ok := value.(types.ManagedObjectReference)
+	if !ok {
+		return nil
+	}
+
+	return &ref
+}
+
+func createInventoryView(ctx context.Context, c *vim25.Client) (*view.ContainerView, error) {
+	m := view.NewManager(c)
+
+	return sx.ExecCallAPI(
+		ctx,
+		func(cctx context.Context) (*view.ContainerView, error) {
+			return m.CreateContainerView(
+				cctx,
+				c.ServiceContent.RootFolder,
+				[]string{"ManagedEntity"},
+				true,
+			)
+		},
+	)
+}
+
+func destroyInventoryView(ctx context.Context, v *view.ContainerView) error {
+	// Destroy even if watching is cancelled.
+	_, err := sx.ExecCallAPI(
+		context.WithoutCancel(ctx),
+		func(cctx context.Context) (int, error) {
+			return 0, v.Destroy(cctx)
+		},
+	)
+	return err
+}
+
+func createInventoryWatcher(
+	ctx context.Context,
+	c *vim25.Client,
+	v *view.ContainerView,
+) (*property.Collector, *property.Filter, error) {
+	pm := property.DefaultCollector(c)
+
+	waiter, err := pm.Create(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spec := types.PropertyFilterSpec{
+		ObjectSet: []types.ObjectSpec{
+			{
+				Obj:  v.Reference(),
+				Skip: types.NewBool(true),
+				SelectSet: []types.BaseSelectionSpec{
+					&types.TraversalSpec{
+						Type: "ContainerView",
+						Path: "view",
+					},
+				},
+			},
+		},
+		PropSet: []types.PropertySpec{
+			{
+				Type:    "ManagedEntity",
+				PathSet: []string{"name", "parent"},
+			},
+			{
+				Type:    "VirtualMachine",
+				PathSet: []string{"resourcePool", parentVAppProperty},
+			},
+			{
+				Type:    "VirtualApp",
+				PathSet: []string{"parentFolder", parentVAppProperty},
+			},
+		},
+	}
+
+	req := types.CreateFilter{
+		Spec:           spec,
+		PartialUpdates: true,
+	}
+
+	filter, err := waiter.CreateFilter(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return waiter, filter, nil
+}
@@ -0,0 +1,209 @@
+package vmomi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+//revive:disable:add-constant
+
+func testObjectUpdate(
+	obj types.ManagedObjectReference,
+	changes ...types.PropertyChange,
+) types.ObjectUpdate {
+	return types.ObjectUpdate{
+		Kind:      types.ObjectUpdateKindEnter,
+		Obj:       obj,
+		ChangeSet: changes,
+	}
+}
+
+func testRef(kind string, value string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{Type: kind, Value: value}
+}
+
+func testInventory() *Inventory {
+	i := NewInventory()
+	i.update([]types.ObjectUpdate{
+		testObjectUpdate(
+			testRef("Datacenter", "datacenter-1"),
+			types.PropertyChange{Name: "name", Val: "DC0"},
+			types.PropertyChange{Name: "parent", Val: testRef("Folder", "group-d1")},
+		),
+		testObjectUpdate(
+			testRef("Folder", "group-v1"),
+			types.PropertyChange{Name: "name", Val: "vm"},
+			types.PropertyChange{Name: "parent", Val: testRef("Datacenter", "datacenter-1")},
+		),
+		testObjectUpdate(
+			testRef("Folder", "group-v2"),
+			types.PropertyChange{Name: "name", Val: "prod"},
+			types.PropertyChange{Name: "parent", Val: testRef("Folder", "group-v1")},
+		),
+		testObjectUpdate(
+			testRef("Folder", "group-h1"),
+			types.PropertyChange{Name: "name", Val: "host"},
+			types.PropertyChange{Name: "parent", Val: testRef("Datacenter", "datacenter-1")},
+		),
+		testObjectUpdate(
+			testRef("ClusterComputeResource", "domain-c1"),
+			types.PropertyChange{Name: "name", Val: "Cluster0"},
+			types.PropertyChange{Name: "parent", Val: testRef("Folder", "group-h1")},
+		),
+		testObjectUpdate(
+			testRef("ResourcePool", "resgroup-1"),
+			types.PropertyChange{Name: "name", Val: "Resources"},
+			types.PropertyChange{
+				Name: "parent",
+				Val:  testRef("ClusterComputeResource", "domain-c1"),
+			},
+		),
+		testObjectUpdate(
+			testRef("VirtualMachine", "vm-1"),
+			types.PropertyChange{Name: "name", Val: "vm01"},
+			types.PropertyChange{Name: "parent", Val: testRef("Folder", "group-v2")},
+			types.PropertyChange{
+				Name: "resourcePool",
+				Val:  testRef("ResourcePool", "resgroup-1"),
+			},
+		),
+	})
+
+	return i
+}
+
+func TestInventory_Enrich_VM(t *testing.T) {
+	i := testInventory()
+	events := []Event{{VM: testString("vm01"), VMID: testString("vm-1")}}
+
+	i.Enrich(&events)
+
+	e := events[0]
+	if e.InventoryPath == nil || *e.InventoryPath != "/DC0/vm/prod/vm01" {
+		t.Errorf("Invalid inventory path: %v", e.InventoryPath)
+	}
+
+	if e.Folder == nil || *e.Folder != "/DC0/vm/prod" {
+		t.Errorf("Invalid folder: %v", e.Folder)
+	}
+
+	if e.ResourcePool == nil || *e.ResourcePool != "/DC0/host/Cluster0/Resources" {
+		t.Errorf("Invalid resource pool: %v", e.ResourcePool)
+	}
+}
+
+func TestInventory_Enrich_Moved(t *testing.T) {
+	i := testInventory()
+	i.update([]types.ObjectUpdate{
+		{
+			Kind: types.ObjectUpdateKindModify,
+			Obj:  testRef("VirtualMachine", "vm-1"),
+			ChangeSet: []types.PropertyChange{
+				{Name: "name", Val: "vm02"},
+				{Name: "parent", Val: testRef("Folder", "group-v1")},
+			},
+		},
+	})
+
+	events := []Event{{VM: testString("vm01"), VMID: testString("vm-1")}}
+
+	i.Enrich(&events)
+
+	e := events[0]
+	if e.InventoryPath == nil || *e.InventoryPath != "/DC0/vm/vm02" {
+		t.Errorf("Invalid inventory path: %v", e.InventoryPath)
+	}
+
+	if e.ResourcePool == nil || *e.ResourcePool != "/DC0/host/Cluster0/Resources" {
+		t.Errorf("Invalid resource pool: %v", e.ResourcePool)
+	}
+}
+
+func TestInventory_Enrich_Removed(t *testing.T) {
+	i := testInventory()
+	i.update([]types.ObjectUpdate{
+		{
+			Kind: types.ObjectUpdateKindLeave,
+			Obj:  testRef("VirtualMachine", "vm-1"),
+		},
+	})
+
+	events := []Event{{VM: testString("vm01"), VMID: testString("vm-1")}}
+
+	i.Enrich(&events)
+
+	if events[0].InventoryPath != nil {
+		t.Errorf("Invalid inventory path: %v", events[0].InventoryPath)
+	}
+}
+
+func TestInventory_Enrich_Nil(_ *testing.T) {
+	var i *Inventory
+	events := []Event{{VM: testString("vm01"), VMID: testString("vm-1")}}
+
+	i.Enrich(&events)
+}
+
+// testVAppVM moves VM into vApp as same as vCenter which unsets parent of VM in vApp.
+func testVAppVM(t *testing.T, model *simulator.Model) string {
+	t.Helper()
+
+	apps := model.Map().All("VirtualApp")
+	if len(apps) == 0 {
+		t.Fatal("vApp not found")
+	}
+
+	vapp, ok := apps[0].(*simulator.VirtualApp)
+	if !ok || len(vapp.Vm) == 0 {
+		t.Fatalf("vApp VM not found: %v", apps[0])
+	}
+
+	vm, ok := model.Map().Get(vapp.Vm[0]).(*simulator.VirtualMachine)
+	if !ok {
+		t.Fatalf("Invalid VM: %v", vapp.Vm[0])
+	}
+
+	ref := vapp.Reference()
+	vm.Parent = nil
+	vm.ParentVApp = &ref
+
+	return vm.Self.Value
+}
+
+func testEqualPath(t *testing.T, name string, actual *string, expected string) {
+	t.Helper()
+
+	if actual == nil || *actual != expected {
+		t.Errorf("Invalid %s: %v", name, actual)
+	}
+}
+
+func TestInventory_Watch_VApp(t *testing.T) {
+	model := simulator.VPX()
+	model.App = 1
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm := testVAppVM(t, model)
+
+		i := NewInventory()
+		maxWaitSeconds := int32(1)
+		err := i.Watch(testTargetContext(ctx, c), &maxWaitSeconds)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		events := []Event{{VM: testString("vm"), VMID: &vm}}
+		i.Enrich(&events)
+
+		e := events[0]
+		testEqualPath(t, "inventory path", e.InventoryPath, "/DC0/vm/DC0_C0_APP0/DC0_C0_APP0_VM0")
+		testEqualPath(t, "folder", e.Folder, "/DC0/vm")
+		testEqualPath(t, "resource pool", e.ResourcePool, "/DC0/host/DC0_C0/Resources/DC0_C0_APP0")
+	}, model)
+}
+
+//revive:enable:add-constant
@@ -2,27 +2,32 @@ package vmomi
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
 
 	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
 	sx "github.com/9506hqwy/vmomi-event-source/pkg/vmomi/sessionex"
 )
 
+const DefaultLocale = "en"
+
+const MaxCatalogFetchConcurrency = 4
+
 const kvMin = int(1)
 const LineContinue = "\\"
 
-var catalogCache = make(map[string]string, 0)
-var catalogTextCache = make(map[string]string, 0)
-
 func GetLocalizationManager(
 	ctx context.Context,
 	c *vim25.Client,
@@ -60,61 +65,224 @@ func GetLocalizationCatalogValue(
 	moduleName string,
 	key string,
 ) (*string, error) {
-	value := getCatalogValueFromCache(key)
-	if value != nil {
-		return value, nil
+	bundle, err := getCatalogBundle(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	url := getLocalizationCatalogURI(lm, locale, moduleName)
-	if url == nil {
-		return nil, nil
+	values, err := loadCatalogModule(ctx, c, lm, bundle, locale, moduleName)
+	if err != nil {
+		return nil, err
 	}
 
-	catalogText := getCatalogTextFromCache(*url)
-	if catalogText != nil {
-		// Return if not found key in parsed content.
-		return nil, nil
+	return NewCatalog(values).Value(key), nil
+}
+
+func loadCatalog(ctx context.Context, c *vim25.Client) (*Catalog, error) {
+	locale, err := sx.GetLocale(ctx, c)
+	if err != nil {
+		return nil, err
 	}
 
-	catalogText, err := getLocalizationCatalog(ctx, c, *url)
+	lm, err := GetLocalizationManager(ctx, c)
 	if err != nil {
 		return nil, err
 	}
 
-	updateCatalogCache(catalogText)
+	ex, err := GetExtensionManager(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := getCatalogBundle(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := getCatalogModules(ex)
+
+	values, err := fetchCatalogModules(
+		modules,
+		func(module string) (map[string]string, error) {
+			return loadCatalogModule(ctx, c, lm, bundle, *locale, module)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	value = getCatalogValueFromCache(key)
-	return value, nil
+	// Keep values per module not to overwrite same key in other module.
+	moduleValues := make(map[string]map[string]string, len(modules))
+	for i, module := range modules {
+		moduleValues[module] = values[i]
+	}
+
+	return NewModuleCatalog(moduleValues, getEventModules(ex)), nil
 }
 
-func cacheLocalizationCatalogAll(
+func getCatalogModules(ex *mo.ExtensionManager) []string {
+	if ex == nil {
+		return []string{"hostdiag"}
+	}
+
+	modules := make([]string, len(ex.ExtensionList))
+	for i, e := range ex.ExtensionList {
+		modules[i] = e.Key
+	}
+
+	return modules
+}
+
+func getEventModules(ex *mo.ExtensionManager) map[string]string {
+	eventModules := map[string]string{}
+	for _, e := range ListExtentionEvent(ex) {
+		eventModules[e.EventID] = e.ModuleName
+	}
+
+	return eventModules
+}
+
+// catalogWatcher reloads catalog if expired or new extension is registered.
+type catalogWatcher struct {
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	catalog *Catalog
+	ttl     time.Duration
+	// Initial extension list is already loaded.
+	watched bool
+	// Reload again after reloading if extension is changed while reloading.
+	reloading bool
+	pending   bool
+}
+
+func (w *catalogWatcher) Update(
 	ctx context.Context,
 	c *vim25.Client,
-	locale string,
-) error {
-	lm, err := GetLocalizationManager(ctx, c)
-	if err != nil {
-		return err
+	updates []types.ObjectUpdate,
+) *Catalog {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changed := containsExtensionListUpdate(updates)
+	if w.reloading {
+		w.pending = w.pending || (changed && w.watched)
+	} else if (changed && w.watched) || w.catalog.IsExpired(w.ttl) {
+		w.startReload(ctx, c)
 	}
 
-	ex, err := GetExtensionManager(ctx, c)
+	w.watched = w.watched || changed
+	return w.catalog
+}
+
+// startReload downloads catalog in background not to block event delivery.
+func (w *catalogWatcher) startReload(ctx context.Context, c *vim25.Client) {
+	w.reloading = true
+	w.wg.Go(func() { w.reload(ctx, c) })
+}
+
+// Wait waits reloading not to use client after logout.
+func (w *catalogWatcher) Wait() {
+	w.wg.Wait()
+}
+
+func (w *catalogWatcher) reload(ctx context.Context, c *vim25.Client) {
+	catalog, err := loadCatalog(ctx, c)
 	if err != nil {
-		return err
+		slog.WarnContext(ctx, "Failed to reload catalog", "error", err)
 	}
 
-	if ex == nil {
-		_, err = GetLocalizationCatalogValue(ctx, c, lm, locale, "hostdiag", "dummy key")
-		return err
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if catalog != nil {
+		w.catalog = catalog
 	}
 
-	for _, e := range ex.ExtensionList {
-		_, err = GetLocalizationCatalogValue(ctx, c, lm, locale, e.Key, "dummy key")
-		if err != nil {
-			return err
+	w.reloading = false
+	if w.pending {
+		w.pending = false
+		w.startReload(ctx, c)
+	}
+}
+
+func containsExtensionListUpdate(updates []types.ObjectUpdate) bool {
+	return slices.ContainsFunc(updates, func(update types.ObjectUpdate) bool {
+		return update.Obj.Type == "ExtensionManager"
+	})
+}
+
+// fetchCatalogModules calls fetch for each module in parallel.
+// The results are returned in order of modules.
+func fetchCatalogModules[T any](
+	modules []string,
+	fetch func(module string) (T, error),
+) ([]T, error) {
+	results := make([]T, len(modules))
+	errs := make([]error, len(modules))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, MaxCatalogFetchConcurrency)
+
+	for i, module := range modules {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i], errs[i] = fetch(module)
+		})
+	}
+
+	wg.Wait()
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func loadCatalogModule(
+	ctx context.Context,
+	c *vim25.Client,
+	lm *mo.LocalizationManager,
+	bundle *CatalogBundle,
+	locale string,
+	moduleName string,
+) (map[string]string, error) {
+	if bundle != nil {
+		// Use offline catalog instead of downloading.
+		text := bundle.Find(locale, moduleName)
+		if text == nil {
+			return nil, nil
 		}
+
+		return parseCatalog(text), nil
 	}
 
-	return nil
+	uri := getLocalizationCatalogURI(lm, locale, moduleName)
+	if uri == nil {
+		return nil, nil
+	}
+
+	key := CatalogKey{
+		VCenter: c.URL().Host,
+		Module:  moduleName,
+		Locale:  locale,
+	}
+
+	return catalogCache.Get(key, getCatalogTTL(ctx), func() (*string, error) {
+		return getLocalizationCatalog(ctx, c, *uri, key)
+	})
+}
+
+func getCatalogTTL(ctx context.Context) time.Duration {
+	ttl, ok := ctx.Value(flag.TargetCatalogTTLKey{}).(int)
+	if !ok {
+		ttl = DefaultCatalogTTLSeconds
+	}
+
+	return time.Duration(ttl) * time.Second
 }
 
 func getLocalizationCatalogURI(
@@ -122,15 +290,27 @@ func getLocalizationCatalogURI(
 	locale string,
 	moduleName string,
 ) *string {
-	uri := findLocalizationCatalogURI(lm, locale, moduleName)
-	if uri != nil {
-		return uri
+	if lm == nil {
+		return nil
+	}
+
+	for _, l := range getCatalogLocales(locale) {
+		uri := findLocalizationCatalogURI(lm, l, moduleName)
+		if uri != nil {
+			return uri
+		}
 	}
 
+	return nil
+}
+
+// getCatalogLocales returns locale, the language
+// and default locale if catalog is not provided for locale.
+func getCatalogLocales(locale string) []string {
 	//revive:disable:add-constant
-	locales := strings.SplitN(locale, "_", 2)
+	language := strings.SplitN(locale, "_", 2)[0]
 	//revive:enable:add-constant
-	return findLocalizationCatalogURI(lm, locales[0], moduleName)
+	return []string{locale, language, DefaultLocale}
 }
 
 func findLocalizationCatalogURI(
@@ -153,68 +333,97 @@ func getLocalizationCatalog(
 	ctx context.Context,
 	c *vim25.Client,
 	uri string,
+	key CatalogKey,
 ) (*string, error) {
-	cached := getCatalogTextFromCache(uri)
-	if cached != nil {
-		return cached, nil
+	store := GetCatalogStore(ctx)
+
+	stored, err := store.Load(key)
+	if err != nil {
+		warnCatalog(ctx, "Failed to load stored catalog", key, err)
 	}
 
-	noVerifySSL, ok := ctx.Value(flag.TargetNoVerifySSLKey{}).(bool)
-	if !ok {
-		return nil, errors.New("target_no_verify_ssl not found in context")
+	downloaded, err := downloadLocalizationCatalog(ctx, c, uri, stored)
+	if err != nil {
+		if stored == nil {
+			return nil, err
+		}
+
+		warnCatalog(ctx, "Failed to download catalog", key, err)
+		return &stored.Text, nil
 	}
 
-	req, err := createRequest(ctx, c, uri)
+	if downloaded != stored {
+		err = store.Save(key, downloaded)
+		if err != nil {
+			warnCatalog(ctx, "Failed to store catalog", key, err)
+		}
+	}
+
+	return &downloaded.Text, nil
+}
+
+func warnCatalog(ctx context.Context, msg string, key CatalogKey, err error) {
+	slog.WarnContext(ctx, msg, "module", key.Module, "locale", key.Locale, "error", err)
+}
+
+// downloadLocalizationCatalog returns stored catalog as is if not modified.
+func downloadLocalizationCatalog(
+	ctx context.Context,
+	c *vim25.Client,
+	uri string,
+	stored *StoredCatalog,
+) (*StoredCatalog, error) {
+	t, err := getTargetTransport(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	tlsConfig := tls.Config{InsecureSkipVerify: noVerifySSL}
-	res, err := send(req, &tlsConfig)
+	req, err := createRequest(ctx, c, uri)
 	if err != nil {
 		return nil, err
 	}
 
-	defer res.Body.Close()
+	setCatalogValidators(req, stored)
 
-	catalogBytes, err := io.ReadAll(res.Body)
+	res, err := send(req, t)
 	if err != nil {
 		return nil, err
 	}
 
-	catalog := string(catalogBytes)
-	updateCatalogTextCache(uri, &catalog)
-
-	return &catalog, nil
-}
+	defer res.Body.Close()
 
-func getCatalogValueFromCache(key string) *string {
-	value, ok := catalogCache[key]
-	if ok && len(value) != Empty {
-		return &value
+	if res.StatusCode == http.StatusNotModified && stored != nil {
+		return stored, nil
 	}
 
-	return nil
-}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", res.Status)
+	}
 
-func updateCatalogCache(catalogText *string) {
-	catalog := parseCatalog(catalogText)
-	for k, v := range catalog {
-		catalogCache[k] = v
+	catalogBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
 	}
+
+	return &StoredCatalog{
+		Text:         string(catalogBytes),
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+	}, nil
 }
 
-func getCatalogTextFromCache(key string) *string {
-	value, ok := catalogTextCache[key]
-	if ok && len(value) != Empty {
-		return &value
+func setCatalogValidators(req *http.Request, stored *StoredCatalog) {
+	if stored == nil {
+		return
 	}
 
-	return nil
-}
+	if len(stored.ETag) != Empty {
+		req.Header.Set("If-None-Match", stored.ETag)
+	}
 
-func updateCatalogTextCache(key string, catalogText *string) {
-	catalogTextCache[key] = *catalogText
+	if len(stored.LastModified) != Empty {
+		req.Header.Set("If-Modified-Since", stored.LastModified)
+	}
 }
 
 //revive:disable:cognitive-complexity
@@ -279,12 +488,10 @@ func createRequest(
 	return req, nil
 }
 
-func send(req *http.Request, tlsConfig *tls.Config) (*http.Response, error) {
+func send(req *http.Request, t *http.Transport) (*http.Response, error) {
 	client := http.Client{}
 
-	client.Transport = &http.Transport{
-		TLSClientConfig: tlsConfig,
-	}
+	client.Transport = t
 
 	res, err := client.Do(req)
 	if err != nil {
@@ -294,7 +501,6 @@ func send(req *http.Request, tlsConfig *tls.Config) (*http.Response, error) {
 	return res, nil
 }
 
-func getCatalogCategory(eventID string) *string {
-	catalogKey := fmt.Sprintf("%s.category", eventID)
-	return getCatalogValueFromCache(catalogKey)
+func getCatalogCategory(catalog *Catalog, eventID string) *string {
+	return catalog.EventValue(eventID, "category")
 }
@@ -1,6 +1,15 @@
 package vmomi
 
-import "testing"
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
 
 //revive:disable:add-constant
 
@@ -80,4 +89,109 @@ key="value"
 	}
 }
 
+func testLocalizationManager() *mo.LocalizationManager {
+	return &mo.LocalizationManager{
+		Catalog: []types.LocalizationManagerMessageCatalog{
+			{ModuleName: "VirtualCenter", CatalogName: "event", Locale: "en", CatalogUri: "/en"},
+			{ModuleName: "VirtualCenter", CatalogName: "event", Locale: "ja", CatalogUri: "/ja"},
+		},
+	}
+}
+
+func Test_getLocalizationCatalogURI_Exact(t *testing.T) {
+	uri := getLocalizationCatalogURI(testLocalizationManager(), "ja", "VirtualCenter")
+
+	if uri == nil || *uri != "/ja" {
+		t.Errorf("Invalid uri: %v", uri)
+	}
+}
+
+func Test_getLocalizationCatalogURI_Language(t *testing.T) {
+	uri := getLocalizationCatalogURI(testLocalizationManager(), "ja_JP", "VirtualCenter")
+
+	if uri == nil || *uri != "/ja" {
+		t.Errorf("Invalid uri: %v", uri)
+	}
+}
+
+func Test_getLocalizationCatalogURI_Default(t *testing.T) {
+	uri := getLocalizationCatalogURI(testLocalizationManager(), "fr", "VirtualCenter")
+
+	if uri == nil || *uri != "/en" {
+		t.Errorf("Invalid uri: %v", uri)
+	}
+}
+
+func testReloadedCatalog(w *catalogWatcher, previous *Catalog) bool {
+	for range 100 {
+		w.mu.Lock()
+		reloaded := w.catalog != previous && !w.reloading
+		w.mu.Unlock()
+
+		if reloaded {
+			return true
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return false
+}
+
+func TestCatalogWatcher_Update_Expired(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		previous := NewCatalog(map[string]string{})
+		w := &catalogWatcher{catalog: previous, ttl: time.Hour}
+
+		// Expire catalog.
+		previous.loaded = time.Now().Add(-2 * time.Hour)
+
+		catalog := w.Update(testTargetContext(ctx, c), c, nil)
+		if catalog != previous {
+			t.Errorf("Not returned previous catalog while reloading: %v", catalog)
+		}
+
+		if !testReloadedCatalog(w, previous) {
+			t.Error("Catalog not reloaded")
+		}
+	})
+}
+
+func Test_getEventModules(t *testing.T) {
+	ex := mo.ExtensionManager{
+		ExtensionList: []types.Extension{
+			{
+				Key:       "com.example",
+				EventList: []types.ExtensionEventTypeInfo{{EventID: "com.example.event"}},
+			},
+		},
+	}
+
+	modules := getEventModules(&ex)
+
+	if modules["com.example.event"] != "com.example" {
+		t.Errorf("Invalid modules: %v", modules)
+	}
+}
+
+func TestCatalogWatcher_Wait(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		previous := NewCatalog(map[string]string{})
+		w := &catalogWatcher{catalog: previous, ttl: time.Hour}
+
+		// Expire catalog.
+		previous.loaded = time.Now().Add(-2 * time.Hour)
+
+		w.Update(testTargetContext(ctx, c), c, nil)
+		w.Wait()
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		if w.reloading || w.catalog == previous {
+			t.Error("Returned before reloading")
+		}
+	})
+}
+
 //revive:enable:add-constant
@@ -0,0 +1,271 @@
+package vmomi
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/event"
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+	sx "github.com/9506hqwy/vmomi-event-source/pkg/vmomi/sessionex"
+)
+
+type MessageLocalizer struct {
+	mu       sync.Mutex
+	ctx      context.Context
+	locales  []string
+	sessions map[string]*localeSession
+}
+
+type localeSession struct {
+	client    *vim25.Client
+	em        *mo.EventManager
+	collector *event.HistoryCollector
+	// Messages read ahead of the events to localize.
+	messages map[int32]string
+}
+
+func newLocaleSession(c *vim25.Client, em *mo.EventManager) *localeSession {
+	return &localeSession{client: c, em: em, messages: map[int32]string{}}
+}
+
+func NewMessageLocalizer(ctx context.Context, locales []string) *MessageLocalizer {
+	m := &MessageLocalizer{
+		ctx:      ctx,
+		locales:  locales,
+		sessions: map[string]*localeSession{},
+	}
+
+	go func() {
+		<-ctx.Done()
+		m.logout()
+	}()
+
+	return m
+}
+
+// Enrich adds messages formatted by vCenter in each locale.
+func (m *MessageLocalizer) Enrich(events *[]Event) {
+	if m == nil || len(*events) == Empty {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, locale := range m.locales {
+		messages, err := m.getLocalizedMessages(locale, events)
+		if err != nil {
+			slog.WarnContext(
+				m.ctx,
+				"Failed to get localized messages",
+				"locale", locale,
+				"error", err,
+			)
+			continue
+		}
+
+		setLocalizedMessages(events, locale, messages)
+	}
+}
+
+func (m *MessageLocalizer) getLocalizedMessages(
+	locale string,
+	events *[]Event,
+) (map[int32]string, error) {
+	s, err := m.getSession(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := readLocalizedMessages(m.ctx, s, events)
+	if fault.Is(err, &types.NotAuthenticated{}) {
+		// Login again at next time because session is expired.
+		delete(m.sessions, locale)
+	}
+
+	return messages, err
+}
+
+// getSession reuses session because login and logout are also events to localize.
+func (m *MessageLocalizer) getSession(locale string) (*localeSession, error) {
+	s, ok := m.sessions[locale]
+	if ok {
+		return s, nil
+	}
+
+	// Login to new session not to change locale of persisted session.
+	ctx := context.WithValue(m.ctx, flag.TargetSessionDirKey{}, "")
+
+	c, err := login(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sx.SetLocale(ctx, c, locale)
+	if err != nil {
+		sx.Logout(ctx, c)
+		return nil, err
+	}
+
+	em, err := getEventManager(ctx, c)
+	if err != nil {
+		sx.Logout(ctx, c)
+		return nil, err
+	}
+
+	s = newLocaleSession(c, em)
+	m.sessions[locale] = s
+	return s, nil
+}
+
+func (m *MessageLocalizer) logout() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Logout sessions not persisted in session directory.
+	ctx := context.WithValue(m.ctx, flag.TargetSessionDirKey{}, "")
+
+	for locale, s := range m.sessions {
+		sx.Logout(ctx, s.client)
+		delete(m.sessions, locale)
+	}
+}
+
+func setLocalizedMessages(events *[]Event, locale string, messages map[int32]string) {
+	for idx := range *events {
+		e := &(*events)[idx]
+
+		message, ok := messages[e.Key]
+		if !ok {
+			continue
+		}
+
+		if e.Messages == nil {
+			e.Messages = map[string]string{}
+		}
+
+		e.Messages[locale] = message
+	}
+}
+
+func readLocalizedMessages(
+	ctx context.Context,
+	s *localeSession,
+	events *[]Event,
+) (map[int32]string, error) {
+	keys := getEventKeys(events)
+
+	err := s.readMessages(ctx, events, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.takeMessages(keys), nil
+}
+
+// readMessages reads forward by collector reused across updates.
+func (s *localeSession) readMessages(
+	ctx context.Context,
+	events *[]Event,
+	keys map[int32]bool,
+) error {
+	if s.hasMessages(keys) {
+		return nil
+	}
+
+	if s.collector != nil {
+		err := s.readNextMessages(ctx, keys)
+		if err != nil || s.hasMessages(keys) {
+			return err
+		}
+
+		// Events polled again are behind the collector.
+		_ = destroyEventCollector(ctx, s.collector)
+		s.collector = nil
+	}
+
+	begin, _ := getEventTimeRange(events)
+
+	collector, _, err := createEventCollectorByTime(ctx, event.NewManager(s.client), &begin, nil)
+	if err != nil {
+		return err
+	}
+
+	s.collector = collector
+	return s.readNextMessages(ctx, keys)
+}
+
+func (s *localeSession) readNextMessages(ctx context.Context, keys map[int32]bool) error {
+	return readNextEventPages(ctx, s.em, nil, s.collector, func(page *[]Event) bool {
+		for _, e := range *page {
+			s.messages[e.Key] = e.FullFormattedMessage
+		}
+
+		return !s.hasMessages(keys)
+	})
+}
+
+func (s *localeSession) hasMessages(keys map[int32]bool) bool {
+	for key := range keys {
+		if _, ok := s.messages[key]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *localeSession) takeMessages(keys map[int32]bool) map[int32]string {
+	last := int32(Empty)
+	messages := make(map[int32]string, len(keys))
+	for key := range keys {
+		message, ok := s.messages[key]
+		if ok {
+			messages[key] = message
+		}
+
+		last = max(last, key)
+	}
+
+	// Keep messages of events not localized yet.
+	for key := range s.messages {
+		if key <= last {
+			delete(s.messages, key)
+		}
+	}
+
+	return messages
+}
+
+func getEventKeys(events *[]Event) map[int32]bool {
+	keys := make(map[int32]bool, len(*events))
+	for _, e := range *events {
+		keys[e.Key] = true
+	}
+
+	return keys
+}
+
+func getEventTimeRange(events *[]Event) (begin time.Time, end time.Time) {
+	begin = (*events)[Empty].CreatedTime
+	end = begin
+
+	for _, e := range *events {
+		if e.CreatedTime.Before(begin) {
+			begin = e.CreatedTime
+		}
+
+		if e.CreatedTime.After(end) {
+			end = e.CreatedTime
+		}
+	}
+
+	return begin, end
+}
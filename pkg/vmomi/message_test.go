@@ -0,0 +1,184 @@
+package vmomi
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/event"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+)
+
+//revive:disable:add-constant
+
+func Test_setLocalizedMessages(t *testing.T) {
+	events := []Event{{Key: 1}, {Key: 2}}
+	messages := map[int32]string{1: "message1"}
+
+	setLocalizedMessages(&events, "ja", messages)
+
+	if len(events[0].Messages) != 1 || events[0].Messages["ja"] != "message1" {
+		t.Errorf("Invalid messages: %v", events[0].Messages)
+	}
+
+	if events[1].Messages != nil {
+		t.Errorf("Invalid messages: %v", events[1].Messages)
+	}
+}
+
+func Test_getEventTimeRange(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []Event{
+		{Key: 1, CreatedTime: created.Add(time.Second)},
+		{Key: 2, CreatedTime: created},
+		{Key: 3, CreatedTime: created.Add(time.Minute)},
+	}
+
+	begin, end := getEventTimeRange(&events)
+
+	if !begin.Equal(created) || !end.Equal(created.Add(time.Minute)) {
+		t.Errorf("Invalid time range: %v - %v", begin, end)
+	}
+}
+
+func testQueryEvents(
+	ctx context.Context,
+	t *testing.T,
+	c *vim25.Client,
+	typeIDs []string,
+) []Event {
+	t.Helper()
+
+	filter := types.EventFilterSpec{EventTypeId: typeIDs}
+
+	evts, err := event.NewManager(c).QueryEvents(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := make([]Event, len(evts))
+	for i, e := range evts {
+		events[i] = Event{Key: e.GetEvent().Key, CreatedTime: e.GetEvent().CreatedTime}
+	}
+
+	return events
+}
+
+func testLocaleSession(ctx context.Context, t *testing.T, c *vim25.Client) *localeSession {
+	t.Helper()
+
+	em, err := getEventManager(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return newLocaleSession(c, em)
+}
+
+func testLocalized(e Event) bool {
+	_, ok := e.Messages["ja"]
+	return ok
+}
+
+func TestMessageLocalizer_Enrich_SessionEvents(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		m := NewMessageLocalizer(testTargetContext(ctx, c), []string{"ja"})
+		m.sessions["ja"] = testLocaleSession(ctx, t, c)
+
+		logins := testQueryEvents(ctx, t, c, []string{"UserLoginSessionEvent"})
+
+		// Localize login events twice as the events of previous localizing.
+		for range 2 {
+			events := testQueryEvents(ctx, t, c, nil)
+			m.Enrich(&events)
+
+			if !slices.ContainsFunc(events, testLocalized) {
+				t.Fatalf("Not localized: %v", events)
+			}
+		}
+
+		events := testQueryEvents(ctx, t, c, []string{"UserLoginSessionEvent"})
+		if len(events) != len(logins) {
+			t.Errorf("Login again: %d -> %d", len(logins), len(events))
+		}
+	})
+}
+
+func TestMessageLocalizer_Enrich_ReuseCollector(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		m := NewMessageLocalizer(testTargetContext(ctx, c), []string{"ja"})
+		s := testLocaleSession(ctx, t, c)
+		m.sessions["ja"] = s
+
+		events := testQueryEvents(ctx, t, c, nil)
+		slices.SortFunc(events, func(a, b Event) int { return int(a.Key - b.Key) })
+
+		first := events[:len(events)-1]
+		m.Enrich(&first)
+
+		collector := s.collector
+
+		// Localize next events by messages read ahead.
+		next := events[len(events)-1:]
+		m.Enrich(&next)
+
+		if s.collector != collector || !testLocalized(next[0]) {
+			t.Errorf("Not reused: %v", next)
+		}
+
+		// Localize events polled again by new collector.
+		again := slices.Clone(first)
+		m.Enrich(&again)
+
+		if s.collector == collector || !slices.ContainsFunc(again, testLocalized) {
+			t.Errorf("Not localized again: %v", again)
+		}
+	})
+}
+
+func testWaitLogout(m *MessageLocalizer) {
+	for range 50 {
+		m.mu.Lock()
+		count := len(m.sessions)
+		m.mu.Unlock()
+
+		if count == 0 {
+			return
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestMessageLocalizer_Logout_SessionDir(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		cctx, cancel := context.WithCancel(testTargetContext(ctx, c))
+		cctx = context.WithValue(cctx, flag.TargetSessionDirKey{}, t.TempDir())
+
+		m := NewMessageLocalizer(cctx, []string{"ja"})
+
+		lc, err := login(testTargetContext(ctx, c))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s := testLocaleSession(ctx, t, lc)
+		m.sessions["ja"] = s
+
+		cancel()
+		testWaitLogout(m)
+
+		us, err := session.NewManager(s.client).UserSession(ctx)
+		if err != nil || us != nil {
+			t.Errorf("Not logged out: %v", err)
+		}
+	})
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,143 @@
+package vmomi
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Format keys in order of preference.
+var formatKeys = []string{
+	"fullFormat",
+	"formatOnDatacenter",
+	"formatOnComputeResource",
+	"formatOnHost",
+	"formatOnVm",
+}
+
+// Argument prefixes of EventEx and ExtendedEvent.
+var argumentPrefixes = []string{
+	"arguments" + AttributeSeparator,
+	"data" + AttributeSeparator,
+}
+
+var placeholderPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// getEventMessage returns the message rendered from catalog
+// if the message formatted by vCenter is empty or raw key.
+func getEventMessage(em *mo.EventManager, catalog *Catalog, e *Event) string {
+	if !isUnformattedMessage(e.FullFormattedMessage, e.EventTypeID) {
+		return e.FullFormattedMessage
+	}
+
+	format := getEventFormat(em, catalog, e.EventTypeID)
+	if format == nil {
+		if len(strings.TrimSpace(e.FullFormattedMessage)) == Empty {
+			return e.EventTypeID
+		}
+
+		return e.FullFormattedMessage
+	}
+
+	return RenderMessage(*format, getMessageArguments(e))
+}
+
+// RenderMessage substitutes `{name}` placeholders by arguments.
+// The unknown placeholders are kept as is.
+func RenderMessage(format string, arguments map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(format, func(placeholder string) string {
+		name := strings.Trim(placeholder, "{}")
+
+		value, ok := arguments[name]
+		if !ok {
+			return placeholder
+		}
+
+		return value
+	})
+}
+
+func isUnformattedMessage(message string, eventTypeID string) bool {
+	message = strings.TrimSpace(message)
+	if len(message) == Empty || message == eventTypeID {
+		return true
+	}
+
+	// Catalog key is returned if the message is not found in catalog.
+	return !strings.ContainsAny(message, " \t") && strings.HasPrefix(message, eventTypeID)
+}
+
+func getEventFormat(em *mo.EventManager, catalog *Catalog, eventTypeID string) *string {
+	for _, key := range formatKeys {
+		format := catalog.EventValue(eventTypeID, key)
+		if format != nil {
+			return format
+		}
+	}
+
+	if em == nil {
+		return nil
+	}
+
+	for _, info := range em.Description.EventInfo {
+		if info.Key == eventTypeID {
+			return getEventDetailFormat(&info)
+		}
+	}
+
+	return nil
+}
+
+func getEventDetailFormat(info *types.EventDescriptionEventDetail) *string {
+	formats := []string{
+		info.FullFormat,
+		info.FormatOnDatacenter,
+		info.FormatOnComputeResource,
+		info.FormatOnHost,
+		info.FormatOnVm,
+	}
+
+	for _, format := range formats {
+		if len(format) != Empty {
+			return &format
+		}
+	}
+
+	return nil
+}
+
+func getMessageArguments(e *Event) map[string]string {
+	attributes := e.GetAttributes()
+	arguments := make(map[string]string, len(attributes))
+
+	for name, value := range attributes {
+		arguments[name] = value
+
+		for _, prefix := range argumentPrefixes {
+			if key, ok := strings.CutPrefix(name, prefix); ok {
+				arguments[key] = value
+			}
+		}
+	}
+
+	setMessageArgument(arguments, "computeResource.name", e.ComputeResource)
+	setMessageArgument(arguments, "datacenter.name", e.Datacenter)
+	setMessageArgument(arguments, "ds.name", e.Datastore)
+	setMessageArgument(arguments, "dvs.name", e.DistributedVirtualSwitch)
+	setMessageArgument(arguments, "host.name", e.Host)
+	setMessageArgument(arguments, "net.name", e.Network)
+	setMessageArgument(arguments, "vm.name", e.VM)
+	setMessageArgument(arguments, "userName", &e.UserName)
+
+	return arguments
+}
+
+func setMessageArgument(arguments map[string]string, name string, value *string) {
+	if value == nil || len(*value) == Empty {
+		return
+	}
+
+	arguments[name] = *value
+}
@@ -0,0 +1,92 @@
+package vmomi
+
+import (
+	"testing"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+//revive:disable:add-constant
+
+func TestRenderMessage(t *testing.T) {
+	message := RenderMessage(
+		"{vm.name} on {host.name} is {state}",
+		map[string]string{"vm.name": "vm01", "host.name": "esxi01"},
+	)
+
+	if message != "vm01 on esxi01 is {state}" {
+		t.Errorf("Invalid message: %v", message)
+	}
+}
+
+func Test_getEventMessage_Formatted(t *testing.T) {
+	e := Event{
+		FullFormattedMessage: "Virtual machine is powered on",
+		EventTypeID:          "VmPoweredOnEvent",
+	}
+
+	message := getEventMessage(nil, nil, &e)
+
+	if message != "Virtual machine is powered on" {
+		t.Errorf("Invalid message: %v", message)
+	}
+}
+
+func Test_getEventMessage_Catalog(t *testing.T) {
+	catalog := NewCatalog(map[string]string{
+		"com.example.event.formatOnHost": "{reason} on host",
+		"com.example.event.fullFormat":   "{reason} on {host.name} by {userName}",
+	})
+
+	e := Event{
+		FullFormattedMessage: "com.example.event",
+		EventTypeID:          "com.example.event",
+		Host:                 testString("esxi01"),
+		UserName:             "root",
+		Attributes:           map[string]string{"arguments.reason": "Failure"},
+	}
+
+	message := getEventMessage(nil, catalog, &e)
+
+	if message != "Failure on esxi01 by root" {
+		t.Errorf("Invalid message: %v", message)
+	}
+}
+
+func Test_getEventMessage_EventDetail(t *testing.T) {
+	em := mo.EventManager{
+		Description: types.EventDescription{
+			EventInfo: []types.EventDescriptionEventDetail{
+				{
+					Key:                "VmPoweredOnEvent",
+					FormatOnDatacenter: "{vm.name} on {host.name} is powered on",
+				},
+			},
+		},
+	}
+
+	e := Event{
+		EventTypeID: "VmPoweredOnEvent",
+		Host:        testString("esxi01"),
+		VM:          testString("vm01"),
+	}
+
+	message := getEventMessage(&em, nil, &e)
+
+	if message != "vm01 on esxi01 is powered on" {
+		t.Errorf("Invalid message: %v", message)
+	}
+}
+
+func Test_getEventMessage_NotFound(t *testing.T) {
+	e := Event{EventTypeID: "com.example.unknown"}
+
+	message := getEventMessage(nil, nil, &e)
+
+	if message != "com.example.unknown" {
+		t.Errorf("Invalid message: %v", message)
+	}
+}
+
+//revive:enable:add-constant
@@ -3,18 +3,35 @@ package vmomi
 import (
 	"context"
 	"errors"
+	"net/http"
+	"os"
+	"strings"
 
+	"github.com/vmware/govmomi/vapi/rest"
 	"github.com/vmware/govmomi/vim25"
 
+	"github.com/9506hqwy/vmomi-event-source/pkg/config"
+	"github.com/9506hqwy/vmomi-event-source/pkg/credential"
 	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+	"github.com/9506hqwy/vmomi-event-source/pkg/transport"
 	sx "github.com/9506hqwy/vmomi-event-source/pkg/vmomi/sessionex"
 )
 
 type ConnInfo struct {
-	URL         string
-	User        string
-	Password    string
-	NoVerifySSL bool
+	Name       string
+	URL        string
+	User       string
+	Password   string
+	TLS        *transport.TLSOptions
+	Proxy      *transport.ProxyOptions
+	Locale     string
+	TokenFile  string
+	CertFile   string
+	KeyFile    string
+	SessionDir string
+	// Source of username and password (e.g. `file:/run/secrets/password`).
+	Credential        string
+	CredentialKeyFile string
 }
 
 func login(ctx context.Context) (*vim25.Client, error) {
@@ -23,14 +40,41 @@ func login(ctx context.Context) (*vim25.Client, error) {
 		return nil, err
 	}
 
-	c, err := sx.Login(ctx, info.URL, info.User, info.Password, info.NoVerifySSL)
+	opts, err := info.LoginOptions(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	c, err := sx.Login(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(info.Locale) != Empty {
+		err = sx.SetLocale(ctx, c, info.Locale)
+		if err != nil {
+			sx.Logout(ctx, c)
+			return nil, err
+		}
+	}
+
 	return c, nil
 }
 
+func loginREST(ctx context.Context, c *vim25.Client) (*rest.Client, error) {
+	info, err := GetTarget(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := info.ResolveCredential(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return sx.LoginREST(ctx, c, cred.Username, cred.Password)
+}
+
 func GetTarget(ctx context.Context) (i *ConnInfo, err error) {
 	url, ok := ctx.Value(flag.TargetURLKey{}).(string)
 	if !ok {
@@ -47,17 +91,119 @@ func GetTarget(ctx context.Context) (i *ConnInfo, err error) {
 		return nil, errors.New("target_password not found in context")
 	}
 
-	noVerifySSL, ok := ctx.Value(flag.TargetNoVerifySSLKey{}).(bool)
+	tlsOptions, err := getTargetTLSOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	locale, ok := ctx.Value(flag.TargetLocaleKey{}).(string)
 	if !ok {
-		return nil, errors.New("target_no_verify_ssl not found in context")
+		// Use session locale if not specified.
+		locale = ""
+	}
+
+	name, ok := ctx.Value(flag.TargetNameKey{}).(string)
+	if !ok || name == "" {
+		name = config.GetHostName(url)
 	}
 
 	c := ConnInfo{
-		URL:         url,
-		User:        user,
-		Password:    password,
-		NoVerifySSL: noVerifySSL,
+		Name:              name,
+		URL:               url,
+		User:              user,
+		Password:          password,
+		TLS:               tlsOptions,
+		Proxy:             getTargetProxyOptions(ctx),
+		Locale:            locale,
+		TokenFile:         flag.OptionalValue(ctx, flag.TargetTokenFileKey{}),
+		CertFile:          flag.OptionalValue(ctx, flag.TargetCertFileKey{}),
+		KeyFile:           flag.OptionalValue(ctx, flag.TargetKeyFileKey{}),
+		SessionDir:        flag.OptionalValue(ctx, flag.TargetSessionDirKey{}),
+		Credential:        flag.OptionalValue(ctx, flag.TargetCredentialKey{}),
+		CredentialKeyFile: flag.OptionalValue(ctx, flag.CredentialKeyFileKey{}),
 	}
 
 	return &c, nil
 }
+
+// ResolveCredential returns username and password read from credential source.
+// The credential is read on every login to use rotated password.
+func (i *ConnInfo) ResolveCredential(ctx context.Context) (*credential.Credential, error) {
+	return credential.Resolve(ctx, i.Credential, i.CredentialKeyFile, i.User, i.Password)
+}
+
+// LoginOptions returns options to login with credential and token read from file.
+func (i *ConnInfo) LoginOptions(ctx context.Context) (*sx.LoginOptions, error) {
+	cred, err := i.ResolveCredential(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var token string
+	if len(i.TokenFile) != Empty {
+		data, err := os.ReadFile(i.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+
+		token = strings.TrimSpace(string(data))
+	}
+
+	return &sx.LoginOptions{
+		Endpoint:   i.URL,
+		Username:   cred.Username,
+		Password:   cred.Password,
+		TLS:        i.TLS,
+		Proxy:      i.Proxy,
+		Token:      token,
+		CertFile:   i.CertFile,
+		KeyFile:    i.KeyFile,
+		SessionDir: i.SessionDir,
+	}, nil
+}
+
+// getTargetTransport returns transport to connect to vCenter other than SOAP.
+func getTargetTransport(ctx context.Context) (*http.Transport, error) {
+	tlsOptions, err := getTargetTLSOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := tlsOptions.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := getTargetProxyOptions(ctx).ProxyFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Transport{
+		Proxy:           proxy,
+		TLSClientConfig: tlsConfig,
+	}, nil
+}
+
+func getTargetTLSOptions(ctx context.Context) (*transport.TLSOptions, error) {
+	noVerifySSL, ok := ctx.Value(flag.TargetNoVerifySSLKey{}).(bool)
+	if !ok {
+		return nil, errors.New("target_no_verify_ssl not found in context")
+	}
+
+	return &transport.TLSOptions{
+		NoVerifySSL: noVerifySSL,
+		CABundle:    flag.OptionalValues(ctx, flag.TargetCABundleKey{}),
+		Thumbprint:  flag.OptionalValue(ctx, flag.TargetThumbprintKey{}),
+		MinVersion:  flag.OptionalValue(ctx, flag.TargetTLSMinVersionKey{}),
+		ServerName:  flag.OptionalValue(ctx, flag.TargetTLSServerNameKey{}),
+	}, nil
+}
+
+func getTargetProxyOptions(ctx context.Context) *transport.ProxyOptions {
+	return &transport.ProxyOptions{
+		URL:     flag.OptionalValue(ctx, flag.TargetProxyKey{}),
+		NoProxy: flag.OptionalValues(ctx, flag.TargetNoProxyKey{}),
+		NoHTTPS: true,
+	}
+}
@@ -0,0 +1,187 @@
+package vmomi
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+	"github.com/9506hqwy/vmomi-event-source/pkg/transport"
+	sx "github.com/9506hqwy/vmomi-event-source/pkg/vmomi/sessionex"
+)
+
+//revive:disable:add-constant
+
+func testSessionKey(ctx context.Context, t *testing.T) string {
+	t.Helper()
+
+	c, err := login(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer sx.Logout(ctx, c)
+
+	s, err := session.NewManager(c).UserSession(ctx)
+	if err != nil || s == nil {
+		t.Fatalf("Invalid session: %v, %v", s, err)
+	}
+
+	return s.Key
+}
+
+func Test_login_SessionDir(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		ctx = testTargetContext(ctx, c)
+		ctx = context.WithValue(ctx, flag.TargetSessionDirKey{}, t.TempDir())
+
+		first := testSessionKey(ctx, t)
+		second := testSessionKey(ctx, t)
+
+		if first != second {
+			t.Errorf("Invalid session: %v, %v", first, second)
+		}
+	})
+}
+
+func Test_login_NoSessionDir(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		ctx = testTargetContext(ctx, c)
+
+		first := testSessionKey(ctx, t)
+		second := testSessionKey(ctx, t)
+
+		if first == second {
+			t.Errorf("Invalid session: %v, %v", first, second)
+		}
+	})
+}
+
+func Test_login_Token(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		tokenFile := filepath.Join(t.TempDir(), "token.xml")
+		token := `<saml2:Assertion xmlns:saml2="urn:oasis:names:tc:SAML:2.0:assertion">` +
+			`<saml2:Subject><saml2:NameID>solution@vsphere.local</saml2:NameID></saml2:Subject>` +
+			`</saml2:Assertion>`
+		err := os.WriteFile(tokenFile, []byte(token+"\n"), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx = testTargetContext(ctx, c)
+		ctx = context.WithValue(ctx, flag.TargetPasswordKey{}, "")
+		ctx = context.WithValue(ctx, flag.TargetTokenFileKey{}, tokenFile)
+
+		tc, err := login(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer sx.Logout(ctx, tc)
+
+		s, err := session.NewManager(tc).UserSession(ctx)
+		if err != nil || s.UserName != "solution@vsphere.local" {
+			t.Errorf("Invalid session: %v, %v", s, err)
+		}
+	})
+}
+
+func testTunnel(w http.ResponseWriter, r *http.Request) {
+	upstream, err := net.Dial("tcp", r.Host)
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	defer upstream.Close()
+
+	w.WriteHeader(http.StatusOK)
+
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return
+	}
+
+	defer conn.Close()
+
+	go func() {
+		_, _ = io.Copy(upstream, conn)
+	}()
+
+	_, _ = io.Copy(conn, upstream)
+}
+
+// testProxy starts proxy which tunnels CONNECT requests.
+func testProxy(t *testing.T, connects *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		connects.Add(1)
+		testTunnel(w, r)
+	}))
+	t.Cleanup(proxy.Close)
+
+	return proxy
+}
+
+func testThumbprint(t *testing.T, host string) string {
+	t.Helper()
+
+	conn, err := tls.Dial("tcp", host, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	return transport.Thumbprint(conn.ConnectionState().PeerCertificates[0])
+}
+
+func Test_login_Proxy(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		var connects atomic.Int32
+		proxy := testProxy(t, &connects)
+
+		// Certificate is verified against vCenter through proxy.
+		ctx = testTargetContext(ctx, c)
+		ctx = context.WithValue(ctx, flag.TargetNoVerifySSLKey{}, false)
+		ctx = context.WithValue(ctx, flag.TargetThumbprintKey{}, testThumbprint(t, c.URL().Host))
+		ctx = context.WithValue(ctx, flag.TargetProxyKey{}, proxy.URL)
+
+		testSessionKey(ctx, t)
+
+		if connects.Load() == 0 {
+			t.Error("Proxy not used")
+		}
+	})
+}
+
+func Test_login_ProxyHTTPS(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		ctx = testTargetContext(ctx, c)
+		ctx = context.WithValue(ctx, flag.TargetProxyKey{}, "https://127.0.0.1:3128")
+
+		_, err := login(ctx)
+		if err == nil {
+			t.Error("https proxy accepted")
+		}
+	})
+}
+
+//revive:enable:add-constant
@@ -2,61 +2,175 @@ package sessionex
 
 import (
 	"context"
+	"crypto/tls"
+	"net/http"
 	"net/url"
 	"time"
 
 	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/session/cache"
+	"github.com/vmware/govmomi/sts"
+	"github.com/vmware/govmomi/vapi/rest"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 
 	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+	"github.com/9506hqwy/vmomi-event-source/pkg/transport"
 )
 
-func Login(
-	ctx context.Context,
-	endpoint string,
-	username string,
-	password string,
-	noVerifySSL bool,
-) (*vim25.Client, error) {
-	u, err := soap.ParseURL(endpoint)
+const Empty = int(0)
+
+type LoginOptions struct {
+	Endpoint string
+	Username string
+	Password string
+	TLS      *transport.TLSOptions
+	Proxy    *transport.ProxyOptions
+	// Token is SAML token issued in advance.
+	Token string
+	// CertFile and KeyFile are solution user certificate to issue token by STS.
+	CertFile string
+	KeyFile  string
+	// SessionDir persists session to reuse across login.
+	SessionDir string
+}
+
+func Login(ctx context.Context, opts *LoginOptions) (*vim25.Client, error) {
+	u, err := soap.ParseURL(opts.Endpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	sc := soap.NewClient(u, noVerifySSL)
-	vc, err := ExecCallAPI(
-		ctx,
-		func(cctx context.Context) (*vim25.Client, error) {
-			return vim25.NewClient(cctx, sc)
-		},
-	)
+	// Persisted session is identified by username and endpoint.
+	u.User = nil
+	if len(opts.Username) != Empty {
+		u.User = url.User(opts.Username)
+	}
+
+	cert, err := loadCertificate(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := opts.TLS.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := opts.Proxy.ProxyFunc()
 	if err != nil {
 		return nil, err
 	}
 
-	sm := session.NewManager(vc)
+	s := cache.Session{
+		URL:         u,
+		DirSOAP:     opts.SessionDir,
+		Insecure:    tlsConfig.InsecureSkipVerify,
+		Passthrough: len(opts.SessionDir) == Empty,
+		LoginSOAP: func(cctx context.Context, c *vim25.Client) error {
+			return loginSOAP(cctx, c, opts, cert)
+		},
+	}
 
-	cred := url.UserPassword(username, password)
+	var vc vim25.Client
 	_, err = ExecCallAPI(
 		ctx,
 		func(cctx context.Context) (int, error) {
-			return 0, sm.Login(cctx, cred)
+			return 0, s.Login(cctx, &vc, configureClient(tlsConfig, proxy, cert))
 		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return vc, nil
+	return &vc, nil
+}
+
+func loginSOAP(
+	ctx context.Context,
+	c *vim25.Client,
+	opts *LoginOptions,
+	cert *tls.Certificate,
+) error {
+	sm := session.NewManager(c)
+
+	if len(opts.Token) == Empty && cert == nil {
+		return sm.Login(ctx, url.UserPassword(opts.Username, opts.Password))
+	}
+
+	signer, err := issueToken(ctx, c, opts.Token, cert)
+	if err != nil {
+		return err
+	}
+
+	header := soap.Header{Security: signer}
+	return sm.LoginByToken(c.WithHeader(ctx, header))
+}
+
+func issueToken(
+	ctx context.Context,
+	c *vim25.Client,
+	token string,
+	cert *tls.Certificate,
+) (*sts.Signer, error) {
+	if len(token) != Empty {
+		// Bearer token, or holder-of-key token if certificate is specified.
+		return &sts.Signer{Token: token, Certificate: cert}, nil
+	}
+
+	// Exchange solution user certificate to holder-of-key token.
+	tokens, err := sts.NewClient(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens.Issue(ctx, sts.TokenRequest{Certificate: cert, Delegatable: true})
+}
+
+func configureClient(
+	tlsConfig *tls.Config,
+	proxy func(*http.Request) (*url.URL, error),
+	cert *tls.Certificate,
+) func(*soap.Client) error {
+	return func(sc *soap.Client) error {
+		// Applied to both new and persisted session.
+		sc.DefaultTransport().TLSClientConfig = tlsConfig.Clone()
+		sc.DefaultTransport().Proxy = proxy
+
+		if cert != nil {
+			sc.SetCertificate(*cert)
+		}
+
+		return nil
+	}
+}
+
+func loadCertificate(opts *LoginOptions) (*tls.Certificate, error) {
+	if len(opts.CertFile) == Empty {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cert, nil
 }
 
 func Logout(ctx context.Context, c *vim25.Client) error {
+	// Keep persisted session to reuse in next login.
+	dir, ok := ctx.Value(flag.TargetSessionDirKey{}).(string)
+	if ok && len(dir) != Empty {
+		return nil
+	}
+
 	sm := session.NewManager(c)
 
+	// Logout even if watching is cancelled.
 	_, err := ExecCallAPI(
-		ctx,
+		context.WithoutCancel(ctx),
 		func(cctx context.Context) (int, error) {
 			return 0, sm.Logout(cctx)
 		},
@@ -65,6 +179,44 @@ func Logout(ctx context.Context, c *vim25.Client) error {
 	return err
 }
 
+func LoginREST(
+	ctx context.Context,
+	c *vim25.Client,
+	username string,
+	password string,
+) (*rest.Client, error) {
+	rc := rest.NewClient(c)
+
+	// Login by SOAP session cookie if password is not specified.
+	var cred *url.Userinfo
+	if len(password) != Empty {
+		cred = url.UserPassword(username, password)
+	}
+
+	_, err := ExecCallAPI(
+		ctx,
+		func(cctx context.Context) (int, error) {
+			return 0, rc.Login(cctx, cred)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return rc, nil
+}
+
+func LogoutREST(ctx context.Context, rc *rest.Client) error {
+	_, err := ExecCallAPI(
+		ctx,
+		func(cctx context.Context) (int, error) {
+			return 0, rc.Logout(cctx)
+		},
+	)
+
+	return err
+}
+
 func GetLocale(ctx context.Context, c *vim25.Client) (*string, error) {
 	sm := session.NewManager(c)
 
@@ -81,6 +233,19 @@ func GetLocale(ctx context.Context, c *vim25.Client) (*string, error) {
 	return &s.Locale, nil
 }
 
+func SetLocale(ctx context.Context, c *vim25.Client, locale string) error {
+	sm := session.NewManager(c)
+
+	_, err := ExecCallAPI(
+		ctx,
+		func(cctx context.Context) (int, error) {
+			return 0, sm.SetLocale(cctx, locale)
+		},
+	)
+
+	return err
+}
+
 func ExecCallAPI[T any](
 	ctx context.Context,
 	fn func(ctx context.Context) (T, error),
@@ -0,0 +1,192 @@
+package vmomi
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+
+	sx "github.com/9506hqwy/vmomi-event-source/pkg/vmomi/sessionex"
+)
+
+type TagCache struct {
+	mu          sync.RWMutex
+	categories  []string
+	attachments map[string]map[string][]string
+}
+
+func NewTagCache(categories []string) *TagCache {
+	return &TagCache{
+		categories:  categories,
+		attachments: map[string]map[string][]string{},
+	}
+}
+
+func (t *TagCache) Refresh(ctx context.Context) error {
+	c, err := login(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer sx.Logout(ctx, c)
+
+	rc, err := loginREST(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	defer sx.LogoutREST(ctx, rc)
+
+	attachments, err := getTagAttachments(ctx, rc, t.categories)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.attachments = attachments
+	return nil
+}
+
+func (t *TagCache) Enrich(events *[]Event) {
+	if t == nil {
+		return
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for idx := range *events {
+		e := &(*events)[idx]
+
+		id := e.EntityID()
+		if id == nil {
+			continue
+		}
+
+		attached, ok := t.attachments[*id]
+		if ok {
+			e.Tags = attached
+		}
+	}
+}
+
+//revive:disable:cognitive-complexity
+
+func getTagAttachments(
+	ctx context.Context,
+	rc *rest.Client,
+	categories []string,
+) (map[string]map[string][]string, error) {
+	m := tags.NewManager(rc)
+
+	cats, err := sx.ExecCallAPI(
+		ctx,
+		func(cctx context.Context) ([]tags.Category, error) {
+			return m.GetCategories(cctx)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := map[string]map[string][]string{}
+
+	for _, cat := range cats {
+		if !slices.Contains(categories, cat.Name) {
+			continue
+		}
+
+		attached, err := getTagAttachmentsForCategory(ctx, m, &cat)
+		if err != nil {
+			return nil, err
+		}
+
+		appendTagAttachments(attachments, cat.Name, attached)
+	}
+
+	// Sort to keep same order in every refresh.
+	for _, entity := range attachments {
+		for _, names := range entity {
+			slices.Sort(names)
+		}
+	}
+
+	return attachments, nil
+}
+
+func appendTagAttachments(
+	attachments map[string]map[string][]string,
+	category string,
+	attached []tags.AttachedObjects,
+) {
+	for _, a := range attached {
+		if a.Tag == nil {
+			continue
+		}
+
+		for _, obj := range a.ObjectIDs {
+			id := obj.Reference().Value
+
+			entity, ok := attachments[id]
+			if !ok {
+				entity = map[string][]string{}
+				attachments[id] = entity
+			}
+
+			entity[category] = append(entity[category], a.Tag.Name)
+		}
+	}
+}
+
+func getTagAttachmentsForCategory(
+	ctx context.Context,
+	m *tags.Manager,
+	category *tags.Category,
+) ([]tags.AttachedObjects, error) {
+	categoryTags, err := sx.ExecCallAPI(
+		ctx,
+		func(cctx context.Context) ([]tags.Tag, error) {
+			return m.GetTagsForCategory(cctx, category.ID)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(categoryTags) == Empty {
+		return nil, nil
+	}
+
+	tagIDs := make([]string, len(categoryTags))
+	for i, tag := range categoryTags {
+		tagIDs[i] = tag.ID
+	}
+
+	attached, err := sx.ExecCallAPI(
+		ctx,
+		func(cctx context.Context) ([]tags.AttachedObjects, error) {
+			return m.ListAttachedObjectsOnTags(cctx, tagIDs)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tag is not populated by list API.
+	for i := range attached {
+		idx := slices.IndexFunc(categoryTags, func(tag tags.Tag) bool {
+			return tag.ID == attached[i].TagID
+		})
+		if idx >= Empty {
+			attached[i].Tag = &categoryTags[idx]
+		}
+	}
+
+	return attached, nil
+}
+
+//revive:enable:cognitive-complexity
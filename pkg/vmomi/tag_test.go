@@ -0,0 +1,90 @@
+package vmomi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+
+	"github.com/9506hqwy/vmomi-event-source/pkg/flag"
+)
+
+//revive:disable:add-constant
+
+func testAttachTag(
+	ctx context.Context,
+	t *testing.T,
+	c *vim25.Client,
+	category string,
+	tag string,
+) string {
+	rc := rest.NewClient(c)
+	err := rc.Login(ctx, simulator.DefaultLogin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := tags.NewManager(rc)
+
+	categoryID, err := m.CreateCategory(ctx, &tags.Category{Name: category})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tagID, err := m.CreateTag(ctx, &tags.Tag{Name: tag, CategoryID: categoryID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm := simulator.Map(ctx).Any("VirtualMachine").Reference()
+	err = m.AttachTag(ctx, tagID, vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return vm.Value
+}
+
+func testTargetContext(ctx context.Context, c *vim25.Client) context.Context {
+	password, _ := simulator.DefaultLogin.Password()
+	ctx = context.WithValue(ctx, flag.TargetURLKey{}, c.URL().String())
+	ctx = context.WithValue(ctx, flag.TargetUserKey{}, simulator.DefaultLogin.Username())
+	ctx = context.WithValue(ctx, flag.TargetPasswordKey{}, password)
+	ctx = context.WithValue(ctx, flag.TargetNoVerifySSLKey{}, true)
+	return ctx
+}
+
+func TestTagCache_Refresh(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm := testAttachTag(ctx, t, c, "team", "infra")
+		testAttachTag(ctx, t, c, "env", "prod")
+
+		cache := NewTagCache([]string{"team"})
+		err := cache.Refresh(testTargetContext(ctx, c))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		events := []Event{{VM: testString("vm"), VMID: &vm}}
+		cache.Enrich(&events)
+
+		tags := events[0].Tags
+		if len(tags) != 1 || len(tags["team"]) != 1 || tags["team"][0] != "infra" {
+			t.Errorf("Invalid tags: %v", tags)
+		}
+	})
+}
+
+func TestTagCache_Enrich_Nil(_ *testing.T) {
+	var cache *TagCache
+	events := []Event{{VM: testString("vm01"), VMID: testString("vm-1")}}
+
+	cache.Enrich(&events)
+}
+
+//revive:enable:add-constant
@@ -0,0 +1,460 @@
+package vmomi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	sx "github.com/9506hqwy/vmomi-event-source/pkg/vmomi/sessionex"
+)
+
+const MaxTaskCount = int32(100)
+
+type Task struct {
+	Key           string
+	DescriptionID string
+	Description   string
+	Entity        *string
+	EntityID      *string
+	State         string
+	QueueTime     time.Time
+	StartTime     *time.Time
+	CompleteTime  *time.Time
+	Initiator     string
+	Error         *string
+	Severity      string
+}
+
+func (t Task) Duration() time.Duration {
+	if t.StartTime == nil || t.CompleteTime == nil {
+		return time.Duration(Empty)
+	}
+
+	return t.CompleteTime.Sub(*t.StartTime)
+}
+
+//revive:disable:cognitive-complexity
+
+func PollTasks(
+	ctx context.Context,
+	maxWaitSeconds *int32,
+	ch chan<- *[]Task,
+) error {
+	c, err := login(ctx)
+	if err != nil {
+		close(ch)
+		return err
+	}
+
+	defer sx.Logout(ctx, c)
+
+	tm := task.NewManager(c)
+
+	collector, err := createTaskCollector(ctx, tm)
+	if err != nil {
+		close(ch)
+		return err
+	}
+
+	defer destroyTaskCollector(ctx, collector)
+
+	descriptions, err := getTaskDescriptions(ctx, c)
+	if err != nil {
+		close(ch)
+		return err
+	}
+
+	waiter, filter, err := createLatestTaskWatcher(ctx, c, collector)
+	if err != nil {
+		close(ch)
+		return err
+	}
+
+	defer destroyPropertyCollector(ctx, waiter)
+	defer destroyPropertyFilter(ctx, filter)
+
+	err = waitUpdateForLatestTask(
+		ctx,
+		waiter,
+		maxWaitSeconds,
+		descriptions,
+		func(tasks *[]Task) {
+			if len(*tasks) != Empty {
+				ch <- tasks
+			}
+		},
+	)
+	if err != nil {
+		close(ch)
+		return err
+	}
+
+	close(ch)
+	return nil
+}
+
+//revive:enable:cognitive-complexity
+
+func ToTask(descriptions map[string]string, info *types.TaskInfo) Task {
+	model := Task{
+		Key:           info.Key,
+		DescriptionID: info.DescriptionId,
+		Description:   getTaskDescription(descriptions, info),
+		State:         string(info.State),
+		QueueTime:     info.QueueTime,
+		StartTime:     info.StartTime,
+		CompleteTime:  info.CompleteTime,
+		Initiator:     getTaskInitiator(info),
+		Severity:      getTaskSeverity(info),
+	}
+
+	if info.Entity != nil {
+		entityName := info.EntityName
+		model.Entity = &entityName
+		model.EntityID = &info.Entity.Value
+	}
+
+	if info.Error != nil {
+		message := info.Error.LocalizedMessage
+		if message == "" {
+			message = strings.TrimPrefix(fmt.Sprintf("%T", info.Error.Fault), "*types.")
+		}
+
+		model.Error = &message
+	}
+
+	return model
+}
+
+func createTaskCollector(
+	ctx context.Context,
+	tm *task.Manager,
+) (*task.HistoryCollector, error) {
+	filter := types.TaskFilterSpec{}
+
+	collector, err := sx.ExecCallAPI(
+		ctx,
+		func(cctx context.Context) (*task.HistoryCollector, error) {
+			return tm.CreateCollectorForTasks(cctx, filter)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = sx.ExecCallAPI(
+		ctx,
+		func(cctx context.Context) (int, error) {
+			return 0, collector.SetPageSize(cctx, MaxTaskCount)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return collector, nil
+}
+
+func destroyTaskCollector(ctx context.Context, collector *task.HistoryCollector) error {
+	// Destroy even if watching is cancelled.
+	_, err := sx.ExecCallAPI(
+		context.WithoutCancel(ctx),
+		func(cctx context.Context) (int, error) {
+			return 0, collector.Destroy(cctx)
+		},
+	)
+	return err
+}
+
+func getTaskDescriptions(ctx context.Context, c *vim25.Client) (map[string]string, error) {
+	pc := property.DefaultCollector(c)
+
+	var tm mo.TaskManager
+	_, err := sx.ExecCallAPI(
+		ctx,
+		func(cctx context.Context) (int, error) {
+			return 0, pc.RetrieveOne(
+				cctx,
+				*c.ServiceContent.TaskManager,
+				[]string{"description"},
+				&tm,
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions := make(map[string]string, len(tm.Description.MethodInfo))
+	for _, info := range tm.Description.MethodInfo {
+		desc := info.GetElementDescription()
+		descriptions[desc.Key] = desc.Label
+	}
+
+	return descriptions, nil
+}
+
+func createLatestTaskWatcher(
+	ctx context.Context,
+	c *vim25.Client,
+	collector *task.HistoryCollector,
+) (*property.Collector, *property.Filter, error) {
+	pm := property.DefaultCollector(c)
+
+	waiter, err := pm.Create(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spec := types.PropertyFilterSpec{
+		ObjectSet: []types.ObjectSpec{
+			{
+				Obj: collector.Reference(),
+			},
+		},
+		PropSet: []types.PropertySpec{
+			{
+				Type:    "TaskHistoryCollector",
+				PathSet: []string{"latestPage"},
+			},
+		},
+	}
+
+	req := types.CreateFilter{
+		Spec:           spec,
+		PartialUpdates: false,
+	}
+
+	filter, err := waiter.CreateFilter(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return waiter, filter, nil
+}
+
+func waitUpdateForLatestTask(
+	ctx context.Context,
+	waiter *property.Collector,
+	maxWaitSeconds *int32,
+	descriptions map[string]string,
+	onUpdatesFn func(*[]Task),
+) error {
+	opt := property.WaitOptions{
+		Options: &types.WaitOptions{
+			MaxObjectUpdates: MaxObjectUpdates,
+			MaxWaitSeconds:   maxWaitSeconds,
+		},
+	}
+
+	w := newTaskWatcher(waiter, descriptions)
+
+	return waiter.WaitForUpdatesEx(ctx, &opt, func(updates []types.ObjectUpdate) bool {
+		tasks := w.update(ctx, updates)
+		onUpdatesFn(&tasks)
+
+		return false
+	})
+}
+
+// taskWatcher reports tasks completed in latest page,
+// and watches `info` of running tasks pushed off from latest page by newer tasks.
+type taskWatcher struct {
+	waiter       *property.Collector
+	descriptions map[string]string
+	// Completed tasks before watching are not notified.
+	completed map[string]bool
+	running   map[string]types.ManagedObjectReference
+	watching  map[string]*property.Filter
+}
+
+func newTaskWatcher(waiter *property.Collector, descriptions map[string]string) *taskWatcher {
+	return &taskWatcher{
+		waiter:       waiter,
+		descriptions: descriptions,
+		running:      map[string]types.ManagedObjectReference{},
+		watching:     map[string]*property.Filter{},
+	}
+}
+
+func (w *taskWatcher) update(ctx context.Context, updates []types.ObjectUpdate) []Task {
+	tasks := []Task{}
+
+	for _, update := range updates {
+		for _, change := range update.ChangeSet {
+			switch v := change.Val.(type) {
+			case types.ArrayOfTaskInfo:
+				tasks = append(tasks, w.updatePage(ctx, v.TaskInfo)...)
+			case types.TaskInfo:
+				tasks = append(tasks, w.updateTask(ctx, &v)...)
+			}
+		}
+	}
+
+	return tasks
+}
+
+func (w *taskWatcher) updatePage(ctx context.Context, page []types.TaskInfo) []Task {
+	var tasks []Task
+	tasks, w.completed = filterCompletedTask(w.descriptions, page, w.completed)
+
+	for _, ref := range getDroppedTasks(page, w.running) {
+		w.watch(ctx, ref)
+	}
+
+	w.running = getRunningTasks(page)
+
+	return tasks
+}
+
+func (w *taskWatcher) updateTask(ctx context.Context, info *types.TaskInfo) []Task {
+	filter, ok := w.watching[info.Task.Value]
+	if !ok || !isTaskCompleted(info) {
+		return nil
+	}
+
+	delete(w.watching, info.Task.Value)
+
+	err := destroyPropertyFilter(ctx, filter)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to unwatch task", "error", err, "task", info.Key)
+	}
+
+	return []Task{ToTask(w.descriptions, info)}
+}
+
+func (w *taskWatcher) watch(ctx context.Context, ref types.ManagedObjectReference) {
+	req := types.CreateFilter{
+		Spec: types.PropertyFilterSpec{
+			ObjectSet: []types.ObjectSpec{
+				{
+					Obj: ref,
+				},
+			},
+			PropSet: []types.PropertySpec{
+				{
+					Type:    "Task",
+					PathSet: []string{"info"},
+				},
+			},
+		},
+		PartialUpdates: false,
+	}
+
+	filter, err := sx.ExecCallAPI(
+		ctx,
+		func(cctx context.Context) (*property.Filter, error) {
+			return w.waiter.CreateFilter(cctx, req)
+		},
+	)
+	if err != nil {
+		// The task may be already removed from server.
+		slog.WarnContext(ctx, "Failed to watch task", "error", err, "task", ref.Value)
+		return
+	}
+
+	w.watching[ref.Value] = filter
+}
+
+func isTaskCompleted(info *types.TaskInfo) bool {
+	return info.State == types.TaskInfoStateSuccess || info.State == types.TaskInfoStateError
+}
+
+func filterCompletedTask(
+	descriptions map[string]string,
+	page []types.TaskInfo,
+	previous map[string]bool,
+) ([]Task, map[string]bool) {
+	tasks := []Task{}
+	completed := make(map[string]bool, len(page))
+
+	for _, info := range page {
+		if !isTaskCompleted(&info) {
+			continue
+		}
+
+		completed[info.Key] = true
+
+		if previous == nil || previous[info.Key] {
+			continue
+		}
+
+		tasks = append(tasks, ToTask(descriptions, &info))
+	}
+
+	return tasks, completed
+}
+
+func getRunningTasks(page []types.TaskInfo) map[string]types.ManagedObjectReference {
+	running := make(map[string]types.ManagedObjectReference, len(page))
+
+	for _, info := range page {
+		if !isTaskCompleted(&info) {
+			running[info.Key] = info.Task
+		}
+	}
+
+	return running
+}
+
+// getDroppedTasks returns the running tasks not included in latest page.
+func getDroppedTasks(
+	page []types.TaskInfo,
+	running map[string]types.ManagedObjectReference,
+) []types.ManagedObjectReference {
+	keys := make(map[string]bool, len(page))
+	for _, info := range page {
+		keys[info.Key] = true
+	}
+
+	dropped := []types.ManagedObjectReference{}
+	for key, ref := range running {
+		if !keys[key] {
+			dropped = append(dropped, ref)
+		}
+	}
+
+	return dropped
+}
+
+func getTaskDescription(descriptions map[string]string, info *types.TaskInfo) string {
+	if info.Description != nil && info.Description.Message != "" {
+		return info.Description.Message
+	}
+
+	description, ok := descriptions[info.DescriptionId]
+	if ok {
+		return description
+	}
+
+	return info.DescriptionId
+}
+
+func getTaskInitiator(info *types.TaskInfo) string {
+	switch reason := info.Reason.(type) {
+	case *types.TaskReasonUser:
+		return reason.UserName
+	case *types.TaskReasonAlarm:
+		return reason.AlarmName
+	case *types.TaskReasonSchedule:
+		return reason.Name
+	default:
+		return "System"
+	}
+}
+
+func getTaskSeverity(info *types.TaskInfo) string {
+	if info.State == types.TaskInfoStateError {
+		return severityError
+	}
+
+	return severityInfo
+}
@@ -0,0 +1,168 @@
+package vmomi
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+//revive:disable:add-constant
+
+func testTaskInfo(key string, state types.TaskInfoState) types.TaskInfo {
+	return types.TaskInfo{
+		Key:           key,
+		Task:          types.ManagedObjectReference{Type: "Task", Value: key},
+		DescriptionId: "VirtualMachine.powerOff",
+		State:         state,
+	}
+}
+
+func testTaskKeys(tasks []Task) []string {
+	keys := []string{}
+	for _, t := range tasks {
+		keys = append(keys, t.Key)
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
+func Test_filterCompletedTask(t *testing.T) {
+	page := []types.TaskInfo{
+		testTaskInfo("task-1", types.TaskInfoStateSuccess),
+		testTaskInfo("task-2", types.TaskInfoStateError),
+		testTaskInfo("task-3", types.TaskInfoStateRunning),
+		testTaskInfo("task-4", types.TaskInfoStateQueued),
+	}
+
+	cases := map[string]struct {
+		previous map[string]bool
+		expected []string
+	}{
+		"initial": {
+			previous: nil,
+			expected: []string{},
+		},
+		"empty": {
+			previous: map[string]bool{},
+			expected: []string{"task-1", "task-2"},
+		},
+		"notified": {
+			previous: map[string]bool{"task-1": true},
+			expected: []string{"task-2"},
+		},
+		"all notified": {
+			previous: map[string]bool{"task-1": true, "task-2": true},
+			expected: []string{},
+		},
+	}
+
+	for name, c := range cases {
+		tasks, completed := filterCompletedTask(nil, page, c.previous)
+
+		if keys := testTaskKeys(tasks); !slices.Equal(keys, c.expected) {
+			t.Errorf("%s: Invalid tasks: %v", name, keys)
+		}
+
+		if len(completed) != 2 || !completed["task-1"] || !completed["task-2"] {
+			t.Errorf("%s: Invalid completed: %v", name, completed)
+		}
+	}
+}
+
+func Test_getDroppedTasks(t *testing.T) {
+	page := []types.TaskInfo{
+		testTaskInfo("task-2", types.TaskInfoStateSuccess),
+		testTaskInfo("task-3", types.TaskInfoStateRunning),
+	}
+
+	running := getRunningTasks([]types.TaskInfo{
+		testTaskInfo("task-1", types.TaskInfoStateRunning),
+		testTaskInfo("task-2", types.TaskInfoStateRunning),
+		testTaskInfo("task-3", types.TaskInfoStateQueued),
+	})
+
+	dropped := getDroppedTasks(page, running)
+	if len(dropped) != 1 || dropped[0].Value != "task-1" {
+		t.Errorf("Invalid dropped: %v", dropped)
+	}
+
+	running = getRunningTasks(page)
+	if len(running) != 1 || running["task-3"].Value != "task-3" {
+		t.Errorf("Invalid running: %v", running)
+	}
+}
+
+func testPowerOffTask(
+	ctx context.Context,
+	t *testing.T,
+	c *vim25.Client,
+) types.ManagedObjectReference {
+	t.Helper()
+
+	vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := vm.PowerOff(ctx)
+	if err == nil {
+		err = task.Wait(ctx)
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return task.Reference()
+}
+
+func testWaitTasks(ctx context.Context, t *testing.T, w *taskWatcher) []Task {
+	t.Helper()
+
+	var tasks []Task
+	err := w.waiter.WaitForUpdatesEx(
+		ctx,
+		&property.WaitOptions{},
+		func(updates []types.ObjectUpdate) bool {
+			tasks = w.update(ctx, updates)
+			return true
+		},
+	)
+	if err != nil {
+		t.Fatalf("WaitForUpdatesEx error: %v", err)
+	}
+
+	return tasks
+}
+
+func TestTaskWatcher_Watch(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		ref := testPowerOffTask(ctx, t, c)
+
+		waiter, err := property.DefaultCollector(c).Create(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w := newTaskWatcher(waiter, nil)
+		w.watch(ctx, ref)
+
+		tasks := testWaitTasks(ctx, t, w)
+		if len(tasks) != 1 || tasks[0].Key != ref.Value || tasks[0].State != "success" {
+			t.Errorf("Invalid tasks: %v", tasks)
+		}
+
+		if len(w.watching) != 0 {
+			t.Errorf("Task still watched: %v", w.watching)
+		}
+	})
+}
+
+//revive:enable:add-constant